@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const defaultLeagueID = 29143
+const defaultConfigFile = "draftee.json"
+
+// Config holds the runtime settings of the dashboard. Values are layered:
+// defaults, then the JSON config file, then DRAFTEE_* environment
+// variables, then command line flags.
+type Config struct {
+	Addr    string `json:"addr"`
+	Leagues []int  `json:"leagues"`
+	// DataDir switches the dashboard to offline mode, reading captured
+	// data-*.json files from this directory instead of the draft API.
+	DataDir string `json:"data_dir"`
+	// ArchiveDir keeps a season history of finished gameweeks here and
+	// serves it when the draft API is unavailable. Empty disables it.
+	ArchiveDir string `json:"archive_dir"`
+	// RecordDir saves timestamped snapshots of the current gameweek's live
+	// data and fixtures here, and enables replaying them on /replay/.
+	RecordDir string `json:"record_dir"`
+	// GoldenDir records every draft API response here as a golden file.
+	GoldenDir string `json:"golden_dir"`
+	// PlaybackDir replaces the draft API with a local stand-in serving the
+	// golden files in this directory.
+	PlaybackDir string `json:"playback_dir"`
+	// UserAgent is sent with every draft API request.
+	UserAgent string `json:"user_agent"`
+	// Dev re-reads render/templates/ from disk on every page load.
+	Dev bool `json:"dev"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Addr:    "0.0.0.0:80",
+		Leagues: []int{defaultLeagueID},
+	}
+}
+
+// parseLeagues turns a comma separated list like "29143,1234" into IDs.
+func parseLeagues(s string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid league id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no league ids given")
+	}
+	return ids, nil
+}
+
+func readConfigFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewDecoder(file).Decode(cfg)
+}
+
+func loadConfig(args []string) (Config, error) {
+	cfg := defaultConfig()
+
+	fs := flag.NewFlagSet("draftee", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to a JSON config file (default "+defaultConfigFile+" if present)")
+	leagues := fs.String("league", "", "comma separated league IDs to serve")
+	addr := fs.String("addr", "", "address to listen on")
+	dataDir := fs.String("data-dir", "", "serve offline from captured data-*.json files in this directory")
+	archiveDir := fs.String("archive-dir", "", "store finished gameweeks in this directory")
+	recordDir := fs.String("record-dir", "", "record live snapshots to this directory and serve /replay/")
+	goldenDir := fs.String("record-http", "", "save every draft API response as a golden file in this directory")
+	playbackDir := fs.String("playback", "", "serve the draft API from golden files in this directory")
+	userAgent := fs.String("user-agent", "", "User-Agent header for draft API requests")
+	dev := fs.Bool("dev", false, "reload templates from disk on every request")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	path := *configPath
+	if path == "" {
+		path = os.Getenv("DRAFTEE_CONFIG")
+	}
+	if path != "" {
+		if err := readConfigFile(path, &cfg); err != nil {
+			return cfg, fmt.Errorf("config %s: %w", path, err)
+		}
+	} else if err := readConfigFile(defaultConfigFile, &cfg); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("config %s: %w", defaultConfigFile, err)
+	}
+
+	if env := os.Getenv("DRAFTEE_LEAGUES"); env != "" {
+		ids, err := parseLeagues(env)
+		if err != nil {
+			return cfg, fmt.Errorf("DRAFTEE_LEAGUES: %w", err)
+		}
+		cfg.Leagues = ids
+	}
+	if env := os.Getenv("DRAFTEE_ADDR"); env != "" {
+		cfg.Addr = env
+	}
+	if env := os.Getenv("DRAFTEE_DATA_DIR"); env != "" {
+		cfg.DataDir = env
+	}
+	if env := os.Getenv("DRAFTEE_ARCHIVE_DIR"); env != "" {
+		cfg.ArchiveDir = env
+	}
+	if env := os.Getenv("DRAFTEE_RECORD_DIR"); env != "" {
+		cfg.RecordDir = env
+	}
+	if env := os.Getenv("DRAFTEE_GOLDEN_DIR"); env != "" {
+		cfg.GoldenDir = env
+	}
+	if env := os.Getenv("DRAFTEE_PLAYBACK_DIR"); env != "" {
+		cfg.PlaybackDir = env
+	}
+	if env := os.Getenv("DRAFTEE_USER_AGENT"); env != "" {
+		cfg.UserAgent = env
+	}
+
+	if *leagues != "" {
+		ids, err := parseLeagues(*leagues)
+		if err != nil {
+			return cfg, fmt.Errorf("-league: %w", err)
+		}
+		cfg.Leagues = ids
+	}
+	if *addr != "" {
+		cfg.Addr = *addr
+	}
+	if *dataDir != "" {
+		cfg.DataDir = *dataDir
+	}
+	if *archiveDir != "" {
+		cfg.ArchiveDir = *archiveDir
+	}
+	if *recordDir != "" {
+		cfg.RecordDir = *recordDir
+	}
+	if *goldenDir != "" {
+		cfg.GoldenDir = *goldenDir
+	}
+	if *playbackDir != "" {
+		cfg.PlaybackDir = *playbackDir
+	}
+	if *userAgent != "" {
+		cfg.UserAgent = *userAgent
+	}
+	if *dev {
+		cfg.Dev = true
+	}
+
+	if len(cfg.Leagues) == 0 {
+		return cfg, errors.New("no leagues configured")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,468 @@
+// Package dashboard is the domain model of a league's gameweek: manager
+// squads with live points, head-to-head matchups, standings and fixtures,
+// built from an fpl.Source and kept fresh by a Poller.
+package dashboard
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"draft.kparajuli.com/m/fpl"
+	"draft.kparajuli.com/m/scoring"
+)
+
+// Dashboard is everything computed for one league's gameweek page.
+type Dashboard struct {
+	LeagueID        int    `json:"league_id"`
+	LeagueName      string `json:"league_name"`
+	Gameweek        int    `json:"gameweek"`
+	CurrentGameweek int    `json:"current_gameweek"`
+	FirstGameweek   int    `json:"first_gameweek"`
+	LastGameweek    int    `json:"last_gameweek"`
+	// Phase is "finished", "live" or "upcoming".
+	Phase     string `json:"phase"`
+	Gameweeks []int  `json:"-"`
+	// Replay is set when the page shows a recorded moment.
+	Replay    *ReplayView   `json:"-"`
+	Matchups  []Matchup     `json:"matchups"`
+	Standings []StandingRow `json:"standings"`
+	// LiveStandings applies the gameweek's unfinished head-to-heads to
+	// Standings. It is only set while the current gameweek is live.
+	LiveStandings []StandingRow   `json:"live_standings"`
+	NextFixtures  []Pairing       `json:"next_fixtures"`
+	Fixtures      []FixtureResult `json:"fixtures"`
+	// Transactions are the gameweek's waivers and free-agent swaps.
+	Transactions []TransactionRow `json:"transactions"`
+	WaiverOrder  []WaiverRow      `json:"waiver_order"`
+	// Trades are the gameweek's trades and any awaiting an answer or veto.
+	Trades []TradeRow `json:"trades"`
+}
+
+// ReplayView is the time slider shown above a replayed dashboard.
+type ReplayView struct {
+	Times []time.Time
+	Index int
+}
+
+// At is the moment being shown.
+func (v ReplayView) At() time.Time {
+	return v.Times[v.Index]
+}
+
+// Matchup is one head-to-head game of the gameweek. Once Finished, the
+// card totals are the feed's official scores.
+type Matchup struct {
+	Home     ManagerCard `json:"home"`
+	Away     ManagerCard `json:"away"`
+	Started  bool        `json:"started"`
+	Finished bool        `json:"finished"`
+}
+
+// ManagerCard is a manager's squad with live points. Error is set
+// instead of Players when the squad could not be loaded.
+type ManagerCard struct {
+	LeagueEntry int    `json:"league_entry"`
+	Manager     string `json:"manager"`
+	Total       int    `json:"total"`
+	Error       string `json:"error,omitempty"`
+	// Bye marks the average opponent of an odd-sized league.
+	Bye     bool        `json:"bye"`
+	Players []PlayerRow `json:"players"`
+	// Subs are the automatic substitutions behind Total.
+	Subs []scoring.Substitution `json:"subs"`
+}
+
+type PlayerRow struct {
+	Element       int    `json:"element"`
+	Name          string `json:"name"`
+	Team          string `json:"team"`
+	Position      string `json:"position"`
+	Minutes       int    `json:"minutes"`
+	GoalsScored   int    `json:"goals_scored"`
+	Assists       int    `json:"assists"`
+	GoalsConceded int    `json:"goals_conceded"`
+	YellowCards   int    `json:"yellow_cards"`
+	Bonus         int    `json:"bonus"`
+	// ProvisionalBonus is set when Bonus includes points derived from
+	// BPS in a fixture the feed hasn't confirmed yet.
+	ProvisionalBonus bool `json:"provisional_bonus"`
+	Points           int  `json:"points"`
+	Bench            bool `json:"bench"`
+	SubIn            bool `json:"sub_in"`
+	SubOut           bool `json:"sub_out"`
+	// Effective is set for the players counted in the projected XI.
+	Effective bool `json:"effective"`
+	// Breakdown recomputes the player's points from the league's
+	// scoring rules and flags disagreement with the live feed.
+	Breakdown scoring.PointsBreakdown `json:"breakdown"`
+	RowClass  string                  `json:"-"`
+	RowStyle  string                  `json:"-"`
+}
+
+type StandingRow struct {
+	Rank          int    `json:"rank"`
+	LeagueEntry   int    `json:"league_entry"`
+	Manager       string `json:"manager"`
+	Won           int    `json:"won"`
+	Drawn         int    `json:"drawn"`
+	Lost          int    `json:"lost"`
+	PointsFor     int    `json:"points_for"`
+	PointsAgainst int    `json:"points_against"`
+	Total         int    `json:"total"`
+	// LastRank is the rank before the latest gameweek, 0 if unknown.
+	LastRank int `json:"last_rank"`
+	// Movement is how many places the row has climbed (negative: fallen).
+	Movement int `json:"movement"`
+
+	rankSort int
+}
+
+// Pairing is a head-to-head game that hasn't been played yet.
+type Pairing struct {
+	HomeEntry int    `json:"home_entry"`
+	Home      string `json:"home"`
+	AwayEntry int    `json:"away_entry"`
+	Away      string `json:"away"`
+}
+
+// FixtureResult is a Premier League fixture with its notable stats.
+type FixtureResult struct {
+	State      string             `json:"state"`
+	Started    bool               `json:"started"`
+	TeamA      string             `json:"team_a"`
+	TeamH      string             `json:"team_h"`
+	TeamAScore int                `json:"team_a_score"`
+	TeamHScore int                `json:"team_h_score"`
+	Home       []StatLine         `json:"home"`
+	Away       []StatLine         `json:"away"`
+	BonusState scoring.BonusState `json:"bonus_state"`
+}
+
+// StatLine lists the players behind one stat, e.g. the goal scorers.
+type StatLine struct {
+	Title   string       `json:"title"`
+	Players []StatPlayer `json:"players"`
+}
+
+type StatPlayer struct {
+	Name      string `json:"name"`
+	Value     int    `json:"value"`
+	ShowValue bool   `json:"show_value"`
+}
+
+// statTitles are the fixture stats shown on the page and their labels.
+var statTitles = map[string]string{
+	"goals_scored":     "⚽",
+	"assists":          "⤵️",
+	"yellow_cards":     "🟨",
+	"red_cards":        "🟥",
+	"own_goals":        "OG",
+	"penalties_saved":  "PS",
+	"penalties_missed": "PM",
+	"bps":              "BPS",
+}
+
+func getFromElVals(title string, elvals []fpl.ElVal, players map[uint16]fpl.Player) (StatLine, bool) {
+	line := StatLine{Title: title}
+	if len(elvals) == 0 {
+		return line, false
+	}
+	for i, elval := range elvals {
+		line.Players = append(line.Players, StatPlayer{
+			Name:      players[uint16(elval.Element)].WebName,
+			Value:     elval.Value,
+			ShowValue: title == "BO" || title == "BPS" || title == "AS" || title == "GS",
+		})
+		if title == "BPS" && i >= 4 {
+			break
+		}
+	}
+	return line, true
+}
+
+func getStats(stats []fpl.Stat, players map[uint16]fpl.Player) ([]StatLine, []StatLine) {
+	var home, away []StatLine
+	for _, el := range stats {
+		title, ok := statTitles[el.S]
+		if !ok {
+			continue
+		}
+		if line, ok := getFromElVals(title, el.H, players); ok {
+			home = append(home, line)
+		}
+		if line, ok := getFromElVals(title, el.A, players); ok {
+			away = append(away, line)
+		}
+	}
+	return home, away
+}
+
+func getFixtureResults(fixtures fpl.Fixtures, players map[uint16]fpl.Player, names labels) ([]FixtureResult, map[uint16]scoring.BonusTally) {
+	var results []FixtureResult
+	bonus := map[uint16]scoring.BonusTally{}
+	for _, game := range fixtures {
+		res := FixtureResult{
+			Started:    game.Finished || game.Started,
+			TeamA:      names.team(game.TeamA),
+			TeamH:      names.team(game.TeamH),
+			TeamAScore: game.TeamAScore,
+			TeamHScore: game.TeamHScore,
+		}
+		if game.Finished {
+			res.State = "FT"
+		} else if game.Started == false {
+			res.State = "NA"
+		} else {
+			res.State = strconv.Itoa(game.Minutes) + "'"
+		}
+
+		if res.Started {
+			res.Home, res.Away = getStats(game.Stats, players)
+		}
+
+		gameBonus, state := scoring.FixtureBonus(game)
+		res.BonusState = state
+		for k, v := range gameBonus {
+			tally := bonus[k]
+			if state == scoring.BonusConfirmed {
+				tally.Confirmed += v
+			} else {
+				tally.Provisional += v
+			}
+			bonus[k] = tally
+		}
+		results = append(results, res)
+	}
+	return results, bonus
+}
+
+// Gameweek phases relative to the game's current event.
+const (
+	phaseFinished = "finished"
+	phaseLive     = "live"
+	phaseUpcoming = "upcoming"
+)
+
+// Build computes the dashboard of a league for gw, or for the
+// current gameweek when gw is 0. Finished head-to-heads show the feed's
+// official scores; upcoming gameweeks show the pairings with the current
+// squads as projected lineups.
+func Build(ctx context.Context, src fpl.Source, leagueID int, gw uint8) (Dashboard, error) {
+	data, err := fetchLeagueData(ctx, src, leagueID, gw)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	event := data.event
+	draft := data.draft
+
+	owners := map[int]string{}
+	for _, user := range draft.LeagueEntries {
+		owners[user.ID] = user.PlayerFirstName
+	}
+
+	names := newLabels(data.bootstrap)
+
+	players := map[uint16]fpl.Player{}
+	for _, pl := range data.bootstrap.Players {
+		players[uint16(pl.ID)] = pl
+	}
+	live := data.live.El
+
+	dash := Dashboard{
+		LeagueID:        leagueID,
+		LeagueName:      draft.League.Name,
+		Gameweek:        int(event),
+		CurrentGameweek: int(data.game.CurrentEvent),
+		FirstGameweek:   draft.League.StartEvent,
+		LastGameweek:    draft.League.StopEvent,
+		Phase:           phaseLive,
+	}
+	switch {
+	case event < data.game.CurrentEvent:
+		dash.Phase = phaseFinished
+	case event > data.game.CurrentEvent:
+		dash.Phase = phaseUpcoming
+	case data.game.CurrentEventFinished:
+		dash.Phase = phaseFinished
+	}
+	for n := dash.FirstGameweek; n <= dash.LastGameweek; n++ {
+		dash.Gameweeks = append(dash.Gameweeks, n)
+	}
+	var bonus map[uint16]scoring.BonusTally
+	dash.Fixtures, bonus = getFixtureResults(data.fixtures, players, names)
+
+	pairs := matchPairs(draft.Matches, int(event))
+
+	// A club is done once all of its fixtures this gameweek are over;
+	// clubs without a fixture are done from the start.
+	clubFixturesOver := map[int]bool{}
+	for _, f := range data.fixtures {
+		over := f.Finished || f.FinishedProvisional
+		for _, team := range []int{f.TeamH, f.TeamA} {
+			if done, seen := clubFixturesOver[team]; seen {
+				clubFixturesOver[team] = done && over
+			} else {
+				clubFixturesOver[team] = over
+			}
+		}
+	}
+	clubDone := func(team int) bool {
+		done, seen := clubFixturesOver[team]
+		return done || !seen
+	}
+
+	buildCard := func(clid int) ManagerCard {
+		card := ManagerCard{LeagueEntry: clid, Manager: managerName(owners, clid)}
+		if clid == averageEntry {
+			card.Bye = true
+			return card
+		}
+		if _, failed := data.clubErrs[clid]; failed {
+			card.Error = "Could not load squad"
+			return card
+		}
+
+		squad := data.clubs[clid].Squad
+		picks := make([]scoring.Pick, 0, len(squad))
+		for _, pl := range squad {
+			player := players[uint16(pl.Element)]
+			picks = append(picks, scoring.Pick{
+				Element:     pl.Element,
+				Position:    pl.Position,
+				ElementType: player.ElementType,
+				Played:      live[uint16(pl.Element)].Stats.Minutes > 0,
+				Done:        clubDone(player.Team),
+			})
+		}
+		effective, subs := scoring.AutoSubs(picks, data.bootstrap.Settings.Squad)
+		card.Subs = subs
+		subbedIn, subbedOut := map[int]bool{}, map[int]bool{}
+		for _, sub := range subs {
+			subbedIn[sub.In] = true
+			subbedOut[sub.Out] = true
+		}
+
+		play := scoring.Starters(data.bootstrap.Settings.Squad)
+		for _, pl := range squad {
+			player := players[uint16(pl.Element)]
+			playerLiveStat := live[uint16(pl.Element)].Stats
+
+			row := PlayerRow{
+				Element:       pl.Element,
+				Name:          player.WebName,
+				Team:          names.team(player.Team),
+				Position:      names.position(player.ElementType),
+				Minutes:       playerLiveStat.Minutes,
+				GoalsScored:   playerLiveStat.GoalsScored,
+				Assists:       playerLiveStat.Assists,
+				GoalsConceded: playerLiveStat.GoalsConceded,
+				YellowCards:   playerLiveStat.YellowCards,
+				Bench:         pl.Position > play,
+				SubIn:         subbedIn[pl.Element],
+				SubOut:        subbedOut[pl.Element],
+				Effective:     effective[pl.Element],
+				Breakdown:     scoring.Score(data.bootstrap.Settings.Scoring, player.ElementType, playerLiveStat),
+			}
+			if row.Bench {
+				row.RowClass = "table-danger"
+				if playerLiveStat.TotalPoints > 5 {
+					row.RowStyle = "font-weight:bold"
+				} else if playerLiveStat.TotalPoints > 1 {
+					row.RowStyle = "font-style:italic"
+				}
+			} else if playerLiveStat.Minutes > 0 {
+				row.RowClass = "table-dark text-light"
+			}
+
+			tally := bonus[uint16(pl.Element)]
+			row.Points, row.Bonus = scoring.ReconcileBonus(playerLiveStat, tally)
+			row.ProvisionalBonus = tally.Provisional > 0 && playerLiveStat.Bonus <= tally.Confirmed
+			if row.Effective {
+				card.Total += row.Points
+			}
+			card.Players = append(card.Players, row)
+		}
+		return card
+	}
+
+	scored, sum := 0, 0
+	for _, pair := range pairs {
+		m := Matchup{
+			Home:     buildCard(pair.Home),
+			Away:     buildCard(pair.Away),
+			Started:  pair.Started,
+			Finished: pair.Finished,
+		}
+		if pair.Finished {
+			m.Home.Total, m.Away.Total = pair.HomePoints, pair.AwayPoints
+		}
+		for _, card := range []ManagerCard{m.Home, m.Away} {
+			if !card.Bye && card.Error == "" {
+				scored++
+				sum += card.Total
+			}
+		}
+		dash.Matchups = append(dash.Matchups, m)
+	}
+	// Until the feed settles it, the average opponent scores the mean of
+	// every loaded squad.
+	if scored > 0 {
+		for i := range dash.Matchups {
+			m := &dash.Matchups[i]
+			for _, card := range []*ManagerCard{&m.Home, &m.Away} {
+				if card.Bye && !m.Finished {
+					card.Total = sum / scored
+				}
+			}
+		}
+	}
+
+	// The API's table counts every head-to-head it has finished. When that
+	// is more than the matches seen finished by this gameweek, as for past
+	// and replayed gameweeks, rebuild the table as it stood from matches.
+	official := 0
+	for _, pos := range draft.Standings {
+		official += pos.MatchesPlayed
+	}
+	if upTo, played := finishedUpTo(draft.Matches, int(event)); official != played {
+		dash.Standings = standingsAt(draft, upTo, data.bootstrap.Settings.League)
+	} else {
+		for _, pos := range draft.Standings {
+			dash.Standings = append(dash.Standings, StandingRow{
+				LeagueEntry:   pos.LeagueEntry,
+				Manager:       owners[pos.LeagueEntry],
+				Won:           pos.MatchesWon,
+				Drawn:         pos.MatchesDrawn,
+				Lost:          pos.MatchesLost,
+				PointsFor:     pos.PointsFor,
+				PointsAgainst: pos.PointsAgainst,
+				Total:         pos.Total,
+				Rank:          pos.Rank,
+				LastRank:      pos.LastRank,
+				rankSort:      pos.RankSort,
+			})
+		}
+		officialStandings(dash.Standings)
+	}
+	if dash.Gameweek == dash.CurrentGameweek && dash.Phase == phaseLive {
+		dash.LiveStandings = liveStandings(dash.Standings, dash.Matchups, data.bootstrap.Settings.League)
+	}
+
+	dash.Transactions = transactionRows(data.transactions.Transactions, int(event), draft, players, names)
+	dash.WaiverOrder = waiverOrder(draft)
+	dash.Trades = tradeRows(data.trades.Trades, int(event), draft, players, data.bootstrap.Settings.Transactions)
+
+	if int(event) < draft.League.StopEvent {
+		for _, pair := range matchPairs(draft.Matches, int(event)+1) {
+			dash.NextFixtures = append(dash.NextFixtures, Pairing{
+				HomeEntry: pair.Home,
+				Home:      managerName(owners, pair.Home),
+				AwayEntry: pair.Away,
+				Away:      managerName(owners, pair.Away),
+			})
+		}
+	}
+
+	return dash, nil
+}
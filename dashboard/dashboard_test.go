@@ -0,0 +1,218 @@
+package dashboard_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"draft.kparajuli.com/m/dashboard"
+	"draft.kparajuli.com/m/fpl"
+	"draft.kparajuli.com/m/fpl/fpltest"
+	"draft.kparajuli.com/m/history"
+)
+
+const testLeague = 29143
+
+// TestBuild builds gameweek 15 end to end from the golden files, through
+// the real client and HTTPSource.
+func TestBuild(t *testing.T) {
+	src := fpltest.NewSource(t, "../fpl/testdata")
+
+	dash, err := dashboard.Build(context.Background(), src, testLeague, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dash.LeagueID != testLeague || dash.Gameweek != 15 || dash.CurrentGameweek != 15 || dash.Phase != "finished" {
+		t.Errorf("league %d gameweek %d of %d, phase %q", dash.LeagueID, dash.Gameweek, dash.CurrentGameweek, dash.Phase)
+	}
+
+	if len(dash.Matchups) != 3 {
+		t.Fatalf("%d matchups, want 3", len(dash.Matchups))
+	}
+	// Finished head-to-heads show the feed's official scores, not the ones
+	// the generated picks add up to (see TestBuildProjected).
+	official := map[int]int{411127: 31, 102091: 31, 472484: 16, 179972: 46, 502341: 41, 102136: 46}
+	for _, m := range dash.Matchups {
+		if !m.Finished {
+			t.Errorf("%s v %s not finished", m.Home.Manager, m.Away.Manager)
+		}
+		for _, card := range []dashboard.ManagerCard{m.Home, m.Away} {
+			if card.Error != "" || len(card.Players) != 15 {
+				t.Errorf("%s: %d players, error %q", card.Manager, len(card.Players), card.Error)
+			}
+			if card.Total != official[card.LeagueEntry] {
+				t.Errorf("%s: total %d, want %d", card.Manager, card.Total, official[card.LeagueEntry])
+			}
+		}
+	}
+
+	if len(dash.Standings) != 6 || dash.Standings[0].LeagueEntry != 102136 || dash.Standings[0].Total != 32 {
+		t.Errorf("standings = %+v", dash.Standings)
+	}
+	if dash.LiveStandings != nil {
+		t.Error("live standings set for a finished gameweek")
+	}
+	if len(dash.Fixtures) != 10 {
+		t.Errorf("%d fixtures, want 10", len(dash.Fixtures))
+	}
+	if len(dash.NextFixtures) != 3 {
+		t.Errorf("%d next fixtures, want 3", len(dash.NextFixtures))
+	}
+	if len(dash.Transactions) != 2 || len(dash.WaiverOrder) != 6 {
+		t.Errorf("%d transactions, %d in the waiver order", len(dash.Transactions), len(dash.WaiverOrder))
+	}
+	// The gameweek's rejected and processed trades, plus the pending one.
+	if len(dash.Trades) != 3 {
+		t.Errorf("%d trades, want 3", len(dash.Trades))
+	}
+}
+
+// reopened serves gameweek 15's head-to-heads as unfinished, so Build
+// scores them from the squads instead of taking the feed's totals.
+type reopened struct {
+	fpl.Source
+}
+
+func (s reopened) LeagueDetails(ctx context.Context, leagueID int) (fpl.Draft, error) {
+	draft, err := s.Source.LeagueDetails(ctx, leagueID)
+	matches := append(fpl.Matches(nil), draft.Matches...)
+	for i := range matches {
+		if matches[i].Event == 15 {
+			matches[i].Finished = false
+		}
+	}
+	draft.Matches = matches
+	return draft, err
+}
+
+// TestBuildProjected scores gameweek 15 from the golden picks. They are
+// generated, so the totals differ from the feed's official ones: each is
+// the starters' points, plus any automatic substitute, plus the bonus the
+// fixtures award.
+func TestBuildProjected(t *testing.T) {
+	src := reopened{fpltest.NewSource(t, "../fpl/testdata")}
+
+	dash, err := dashboard.Build(context.Background(), src, testLeague, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]int{411127: 50, 102091: 37, 472484: 28, 179972: 31, 502341: 46, 102136: 28}
+	for _, m := range dash.Matchups {
+		if m.Finished {
+			t.Errorf("%s v %s finished", m.Home.Manager, m.Away.Manager)
+		}
+		for _, card := range []dashboard.ManagerCard{m.Home, m.Away} {
+			effective, sum := 0, 0
+			for i, row := range card.Players {
+				if row.Bench != (i >= 11) {
+					t.Errorf("%s: %s bench = %v", card.Manager, row.Name, row.Bench)
+				}
+				if row.Effective {
+					effective++
+					sum += row.Points
+				}
+			}
+			if effective != 11 || card.Total != sum {
+				t.Errorf("%s: %d effective players scoring %d, total %d", card.Manager, effective, sum, card.Total)
+			}
+			if card.Total != want[card.LeagueEntry] {
+				t.Errorf("%s: total %d, want %d", card.Manager, card.Total, want[card.LeagueEntry])
+			}
+		}
+	}
+	// Rahul's midfielder in position 6 didn't play; the first outfield
+	// substitute on the bench comes on.
+	for _, m := range dash.Matchups {
+		for _, card := range []dashboard.ManagerCard{m.Home, m.Away} {
+			if subs := len(card.Subs); (card.LeagueEntry == 102091) != (subs == 1) {
+				t.Errorf("%s: %d substitutions", card.Manager, subs)
+			}
+		}
+	}
+}
+
+// TestBuildPastGameweek rebuilds gameweek 14's table from the matches;
+// the golden files hold no gameweek 14 live data or picks.
+func TestBuildPastGameweek(t *testing.T) {
+	src := fpltest.NewSource(t, "../fpl/testdata")
+
+	dash, err := dashboard.Build(context.Background(), src, testLeague, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGW14Standings(t, dash.Standings)
+}
+
+// TestBuildReplay replays gameweek 15 while its fixtures were still being
+// played. The table under it is the one gameweek 14 left, not today's.
+func TestBuildReplay(t *testing.T) {
+	src := fpltest.NewSource(t, "../fpl/testdata")
+	dir := t.TempDir()
+	at := time.Date(2023, 12, 9, 16, 0, 0, 0, time.UTC)
+	stamp := strconv.FormatInt(at.UnixNano(), 10)
+
+	var fixtures []map[string]interface{}
+	readGolden(t, "api-event-15-fixtures.json", &fixtures)
+	for _, f := range fixtures {
+		f["finished"], f["finished_provisional"] = false, false
+	}
+	body, err := json.Marshal(fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := os.ReadFile("../fpl/testdata/api-event-15-live.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "gw15"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for kind, b := range map[string][]byte{"fixtures": body, "live": live} {
+		if err := os.WriteFile(filepath.Join(dir, "gw15", stamp+"-"+kind+".json"), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replay := history.NewReplay(src, history.NewRecording(dir), 15, at)
+	dash, err := dashboard.Build(context.Background(), replay, testLeague, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dash.Phase != "live" {
+		t.Errorf("phase %q, want live", dash.Phase)
+	}
+	checkGW14Standings(t, dash.Standings)
+}
+
+func readGolden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("../fpl/testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkGW14Standings compares rows with the table as gameweek 14 ended.
+func checkGW14Standings(t *testing.T, rows []dashboard.StandingRow) {
+	t.Helper()
+	want := []struct{ entry, total, pointsFor int }{
+		{102136, 29, 699}, {472484, 27, 621}, {102091, 24, 669},
+		{411127, 22, 582}, {502341, 12, 471}, {179972, 10, 554},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("%d standings, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.LeagueEntry != w.entry || row.Total != w.total || row.PointsFor != w.pointsFor {
+			t.Errorf("rank %d = %d with %d (%d for), want %d with %d (%d for)", i+1, row.LeagueEntry, row.Total, row.PointsFor, w.entry, w.total, w.pointsFor)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package dashboard
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+const (
+	// fetchConcurrency bounds how many upstream requests run at once.
+	fetchConcurrency = 4
+	// fetchDeadline is shared by every request made for one dashboard.
+	fetchDeadline = 20 * time.Second
+)
+
+// leagueData is everything the dashboard of one league needs for one
+// gameweek. Squads that failed to load are in clubErrs instead of clubs.
+type leagueData struct {
+	event uint8
+	// picksEvent is the gameweek squads were loaded for. It is the
+	// current gameweek when event is still in the future, as that is
+	// the best projection of the lineups.
+	picksEvent uint8
+	game       fpl.Game
+	draft      fpl.Draft
+	bootstrap  fpl.Bootstrap
+	live       fpl.Live
+	fixtures   fpl.Fixtures
+	// transactions are the whole season's, as the endpoint has no filter.
+	transactions fpl.Transactions
+	trades       fpl.Trades
+	clubs        map[int]fpl.Club
+	clubErrs     map[int]error
+}
+
+// fetchLeagueData loads game state and league details first, then the
+// bootstrap, live, fixtures, transactions, trades and every manager's
+// picks in parallel. Only the game, league and bootstrap are required;
+// the rest degrade to empty values so one bad response can't take down
+// the page.
+// A gw of 0 means the current gameweek.
+func fetchLeagueData(ctx context.Context, src fpl.Source, leagueID int, gw uint8) (leagueData, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchDeadline)
+	defer cancel()
+
+	data := leagueData{
+		clubs:    map[int]fpl.Club{},
+		clubErrs: map[int]error{},
+	}
+
+	var wg sync.WaitGroup
+	var gameErr, draftErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		data.game, gameErr = src.Game(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		data.draft, draftErr = src.LeagueDetails(ctx, leagueID)
+	}()
+	wg.Wait()
+	if gameErr != nil {
+		return data, gameErr
+	}
+	if draftErr != nil {
+		return data, draftErr
+	}
+	data.event = data.game.CurrentEvent
+	if gw != 0 {
+		data.event = gw
+	}
+	data.picksEvent = data.event
+	if data.event > data.game.CurrentEvent {
+		data.picksEvent = data.game.CurrentEvent
+	}
+
+	var mu sync.Mutex
+	var bootstrapErr error
+	sem := make(chan struct{}, fetchConcurrency)
+	spawn := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn()
+		}()
+	}
+
+	spawn(func() {
+		data.bootstrap, bootstrapErr = src.Bootstrap(ctx)
+	})
+	spawn(func() {
+		var err error
+		if data.live, err = src.Live(ctx, data.event); err != nil {
+			log.Printf("live gw %d: %v", data.event, err)
+		}
+	})
+	spawn(func() {
+		var err error
+		if data.fixtures, err = src.Fixtures(ctx, data.event); err != nil {
+			log.Printf("fixtures gw %d: %v", data.event, err)
+		}
+	})
+	spawn(func() {
+		var err error
+		if data.transactions, err = src.Transactions(ctx, leagueID); err != nil {
+			log.Printf("transactions league %d: %v", leagueID, err)
+		}
+	})
+	spawn(func() {
+		var err error
+		if data.trades, err = src.Trades(ctx, leagueID); err != nil {
+			log.Printf("trades league %d: %v", leagueID, err)
+		}
+	})
+	for _, user := range data.draft.LeagueEntries {
+		id, entry := user.ID, uint32(user.EntryID)
+		spawn(func() {
+			club, err := src.EntryPicks(ctx, entry, data.picksEvent)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("picks for entry %d: %v", entry, err)
+				data.clubErrs[id] = err
+				return
+			}
+			data.clubs[id] = club
+		})
+	}
+	wg.Wait()
+
+	return data, bootstrapErr
+}
@@ -0,0 +1,41 @@
+package dashboard
+
+import "draft.kparajuli.com/m/fpl"
+
+// unknownLabel is shown for IDs the bootstrap doesn't know about.
+const unknownLabel = "NA"
+
+// labels resolves club and position IDs to the short names used by the
+// current season's bootstrap-static, e.g. team 1 -> "ARS", type 2 -> "DEF".
+type labels struct {
+	teams     map[int]string
+	positions map[int]string
+}
+
+func newLabels(bootstrap fpl.Bootstrap) labels {
+	l := labels{
+		teams:     map[int]string{},
+		positions: map[int]string{},
+	}
+	for _, t := range bootstrap.Teams {
+		l.teams[t.ID] = t.ShortName
+	}
+	for _, et := range bootstrap.ElementTypes {
+		l.positions[et.ID] = et.SingularNameShort
+	}
+	return l
+}
+
+func (l labels) team(id int) string {
+	if name, ok := l.teams[id]; ok {
+		return name
+	}
+	return unknownLabel
+}
+
+func (l labels) position(id int) string {
+	if name, ok := l.positions[id]; ok {
+		return name
+	}
+	return unknownLabel
+}
@@ -0,0 +1,46 @@
+package dashboard
+
+import "draft.kparajuli.com/m/fpl"
+
+// averageEntry stands in for the missing opponent when a league has an
+// odd number of managers; the feed sends it as a null league entry.
+const averageEntry = 0
+
+const averageName = "AVERAGE"
+
+// h2hMatch is one head-to-head game with the feed's official result.
+type h2hMatch struct {
+	Home       int
+	Away       int
+	HomePoints int
+	AwayPoints int
+	Started    bool
+	Finished   bool
+}
+
+// matchPairs returns every head-to-head game of a gameweek in feed order,
+// however many managers the league has.
+func matchPairs(matches fpl.Matches, gw int) []h2hMatch {
+	var pairs []h2hMatch
+	for _, m := range matches {
+		if m.Event == gw {
+			pairs = append(pairs, h2hMatch{
+				Home:       m.LeagueEntry1,
+				Away:       m.LeagueEntry2,
+				HomePoints: m.LeagueEntry1Points,
+				AwayPoints: m.LeagueEntry2Points,
+				Started:    m.Started,
+				Finished:   m.Finished,
+			})
+		}
+	}
+	return pairs
+}
+
+// managerName resolves a league entry, including the average opponent.
+func managerName(owners map[int]string, entry int) string {
+	if entry == averageEntry {
+		return averageName
+	}
+	return owners[entry]
+}
@@ -0,0 +1,179 @@
+package dashboard
+
+import (
+	"context"
+	"sort"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// playerStatuses labels the bootstrap's player availability codes.
+var playerStatuses = map[string]string{
+	"a": "Available",
+	"d": "Doubtful",
+	"i": "Injured",
+	"s": "Suspended",
+	"n": "Not available",
+	"u": "Unavailable",
+}
+
+// PoolPlayer is one footballer and who, if anyone, owns him in the
+// league.
+type PoolPlayer struct {
+	Element       int     `json:"element"`
+	Name          string  `json:"name"`
+	Team          string  `json:"team"`
+	Position      string  `json:"position"`
+	Status        string  `json:"status"`
+	News          string  `json:"news"`
+	Form          float64 `json:"form"`
+	PointsPerGame float64 `json:"points_per_game"`
+	TotalPoints   int     `json:"total_points"`
+	EventPoints   int     `json:"event_points"`
+	// LeagueEntry and Owner are zero for players nobody owns, who are
+	// either on Waivers or free to pick up.
+	LeagueEntry     int    `json:"league_entry"`
+	Owner           string `json:"owner"`
+	Waivers         bool   `json:"waivers"`
+	InAcceptedTrade bool   `json:"in_accepted_trade"`
+
+	statusCode string
+}
+
+// Owned reports whether a manager holds the player.
+func (p PoolPlayer) Owned() bool {
+	return p.LeagueEntry != 0
+}
+
+// PlayerFilter narrows the player list. Zero fields match everything.
+type PlayerFilter struct {
+	Position string  `json:"position"` // short name, e.g. "DEF"
+	Team     string  `json:"team"`     // short name, e.g. "ARS"
+	Status   string  `json:"status"`   // bootstrap code, e.g. "a"
+	MinForm  float64 `json:"min_form"`
+	// MinPoints is the least total points this season.
+	MinPoints int `json:"min_points"`
+	// Ownership is "owned", "free" or empty for both.
+	Ownership string `json:"ownership"`
+}
+
+func (f PlayerFilter) match(p PoolPlayer) bool {
+	switch {
+	case f.Position != "" && f.Position != p.Position,
+		f.Team != "" && f.Team != p.Team,
+		f.Status != "" && f.Status != p.statusCode,
+		f.MinForm != 0 && p.Form < f.MinForm,
+		f.MinPoints != 0 && p.TotalPoints < f.MinPoints,
+		f.Ownership == "owned" && !p.Owned(),
+		f.Ownership == "free" && p.Owned():
+		return false
+	}
+	return true
+}
+
+// PlayersView is the league's player pool, best season first.
+type PlayersView struct {
+	LeagueID   int          `json:"league_id"`
+	LeagueName string       `json:"league_name"`
+	Filter     PlayerFilter `json:"filter"`
+	Players    []PoolPlayer `json:"players"`
+
+	// Positions, Teams and Statuses are the filter's choices.
+	Positions []string          `json:"-"`
+	Teams     []string          `json:"-"`
+	Statuses  map[string]string `json:"-"`
+}
+
+// owner is who holds a player in a league.
+type owner struct {
+	leagueEntry     int
+	waivers         bool
+	inAcceptedTrade bool
+}
+
+// ownershipIndex maps element IDs to their league entry. Players missing
+// from the index are free agents.
+func ownershipIndex(statuses fpl.ElementStatuses, draft fpl.Draft) map[int]owner {
+	leagueEntries, _ := entryOwners(draft)
+	index := map[int]owner{}
+	for _, st := range statuses.ElementStatus {
+		o := owner{waivers: st.Status == "w", inAcceptedTrade: st.InAcceptedTrade}
+		if st.Owner != nil {
+			o.leagueEntry = leagueEntries[*st.Owner]
+		}
+		index[st.Element] = o
+	}
+	return index
+}
+
+// BuildPlayers lists the players matching filter with their owners in
+// the league.
+func BuildPlayers(ctx context.Context, src fpl.Source, leagueID int, filter PlayerFilter) (PlayersView, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchDeadline)
+	defer cancel()
+
+	draft, err := src.LeagueDetails(ctx, leagueID)
+	if err != nil {
+		return PlayersView{}, err
+	}
+	bootstrap, err := src.Bootstrap(ctx)
+	if err != nil {
+		return PlayersView{}, err
+	}
+	statuses, err := src.ElementStatus(ctx, leagueID)
+	if err != nil {
+		return PlayersView{}, err
+	}
+
+	names := newLabels(bootstrap)
+	_, managers := entryOwners(draft)
+	owners := ownershipIndex(statuses, draft)
+	view := PlayersView{
+		LeagueID:   leagueID,
+		LeagueName: draft.League.Name,
+		Filter:     filter,
+		Statuses:   playerStatuses,
+	}
+	for _, et := range bootstrap.ElementTypes {
+		view.Positions = append(view.Positions, et.SingularNameShort)
+	}
+	for _, t := range bootstrap.Teams {
+		view.Teams = append(view.Teams, t.ShortName)
+	}
+	sort.Strings(view.Teams)
+
+	for _, pl := range bootstrap.Players {
+		o := owners[pl.ID]
+		row := PoolPlayer{
+			Element:         pl.ID,
+			Name:            pl.WebName,
+			Team:            names.team(pl.Team),
+			Position:        names.position(pl.ElementType),
+			Status:          playerStatuses[pl.Status],
+			News:            pl.News,
+			Form:            float64(pl.Form),
+			PointsPerGame:   float64(pl.PointsPerGame),
+			TotalPoints:     pl.TotalPoints,
+			EventPoints:     pl.EventPoints,
+			LeagueEntry:     o.leagueEntry,
+			Owner:           managers[o.leagueEntry],
+			Waivers:         o.waivers,
+			InAcceptedTrade: o.inAcceptedTrade,
+			statusCode:      pl.Status,
+		}
+		if row.Status == "" {
+			row.Status = pl.Status
+		}
+		if filter.match(row) {
+			view.Players = append(view.Players, row)
+		}
+	}
+	sort.SliceStable(view.Players, func(i, j int) bool {
+		a, b := view.Players[i], view.Players[j]
+		if a.TotalPoints != b.TotalPoints {
+			return a.TotalPoints > b.TotalPoints
+		}
+		return a.Form > b.Form
+	})
+	return view, nil
+}
@@ -0,0 +1,140 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+var ErrGameweekOutOfRange = errors.New("gameweek outside the league's season")
+
+// Poll intervals by match state.
+const (
+	pollLive     = 30 * time.Second // a fixture is being played
+	pollMatchday = 5 * time.Minute  // gameweek running, nothing kicked off
+	pollIdle     = 30 * time.Minute // gameweek finished
+	pollRetry    = time.Minute      // last refresh failed
+)
+
+// Snapshot is a computed dashboard together with when it was built.
+type Snapshot struct {
+	Dashboard Dashboard
+	Built     time.Time
+}
+
+// Poller rebuilds one league's dashboard in the background so page loads
+// never wait on upstream calls. A failed refresh keeps the previous
+// snapshot in place.
+type Poller struct {
+	src      fpl.Source
+	leagueID int
+
+	mu      sync.RWMutex
+	last    Snapshot
+	ready   bool
+	lastErr error
+}
+
+func NewPoller(src fpl.Source, leagueID int) *Poller {
+	return &Poller{src: src, leagueID: leagueID}
+}
+
+// Snapshot returns the last good dashboard, if one has been built yet.
+func (p *Poller) Snapshot() (Snapshot, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.last, p.ready
+}
+
+// Err returns the error of the most recent refresh, if it failed.
+func (p *Poller) Err() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastErr
+}
+
+func (p *Poller) refresh(ctx context.Context) error {
+	dash, err := Build(ctx, p.src, p.leagueID, 0)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+	if err != nil {
+		return err
+	}
+	p.last = Snapshot{Dashboard: dash, Built: time.Now()}
+	p.ready = true
+	return nil
+}
+
+// Gameweek returns the dashboard for gw: the snapshot when gw is the
+// gameweek it was built for, otherwise a fresh build. gw must lie within
+// the league's season.
+func (p *Poller) Gameweek(ctx context.Context, gw int) (Dashboard, error) {
+	snap, ready := p.Snapshot()
+	if ready && (gw == 0 || gw == snap.Dashboard.Gameweek) {
+		return snap.Dashboard, nil
+	}
+	if ready && (gw < snap.Dashboard.FirstGameweek || gw > snap.Dashboard.LastGameweek) {
+		return Dashboard{}, ErrGameweekOutOfRange
+	}
+	if gw < 0 || gw > 255 {
+		return Dashboard{}, ErrGameweekOutOfRange
+	}
+
+	dash, err := Build(ctx, p.src, p.leagueID, uint8(gw))
+	if err != nil {
+		return dash, err
+	}
+	if gw < dash.FirstGameweek || gw > dash.LastGameweek {
+		return Dashboard{}, ErrGameweekOutOfRange
+	}
+	return dash, nil
+}
+
+// nextInterval picks how long to sleep based on the state of the current
+// gameweek's fixtures.
+func (p *Poller) nextInterval(ctx context.Context) time.Duration {
+	game, err := p.src.Game(ctx)
+	if err != nil {
+		return pollRetry
+	}
+	if game.CurrentEventFinished {
+		return pollIdle
+	}
+
+	fixtures, err := p.src.Fixtures(ctx, game.CurrentEvent)
+	if err != nil {
+		return pollRetry
+	}
+	for _, f := range fixtures {
+		if f.Started && !f.Finished {
+			return pollLive
+		}
+	}
+	return pollMatchday
+}
+
+// Run refreshes until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) {
+	for {
+		wait := pollRetry
+		if err := p.refresh(ctx); err != nil {
+			log.Printf("league %d: refresh: %v", p.leagueID, err)
+		} else {
+			wait = p.nextInterval(ctx)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
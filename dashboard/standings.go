@@ -0,0 +1,174 @@
+package dashboard
+
+import (
+	"sort"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// standingLess is the official head-to-head ordering: league points, then
+// points for.
+func standingLess(a, b StandingRow) bool {
+	if a.Total != b.Total {
+		return a.Total > b.Total
+	}
+	return a.PointsFor > b.PointsFor
+}
+
+// rankStandings orders the table with standingLess and ranks the rows.
+// Rows that can't be told apart share a rank, as on the official site.
+func rankStandings(rows []StandingRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return standingLess(rows[i], rows[j])
+	})
+	for i := range rows {
+		rows[i].Rank = i + 1
+		if i > 0 && !standingLess(rows[i-1], rows[i]) {
+			rows[i].Rank = rows[i-1].Rank
+		}
+	}
+}
+
+// officialStandings orders the confirmed table by the API's rank_sort and
+// sets Movement from last_rank. Before the first gameweek is processed
+// the API leaves the ranks null, so the table is ranked locally instead.
+func officialStandings(rows []StandingRow) {
+	for _, r := range rows {
+		if r.rankSort == 0 || r.Rank == 0 {
+			rankStandings(rows)
+			return
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].rankSort < rows[j].rankSort
+	})
+	for i := range rows {
+		if rows[i].LastRank != 0 {
+			rows[i].Movement = rows[i].LastRank - rows[i].Rank
+		}
+	}
+}
+
+// liveStandings returns the table as it would stand if every unfinished
+// head-to-head ended with the current scores. Win, draw and loss points
+// come from the league settings; LastRank and Movement compare each row
+// with its confirmed rank. Matchups where either squad failed to load
+// are left out rather than scored as 0.
+func liveStandings(confirmed []StandingRow, matchups []Matchup, league fpl.LeagueSettings) []StandingRow {
+	rows := append([]StandingRow(nil), confirmed...)
+	byEntry := map[int]*StandingRow{}
+	for i := range rows {
+		byEntry[rows[i].LeagueEntry] = &rows[i]
+	}
+
+	apply := func(card, opp ManagerCard) {
+		row, ok := byEntry[card.LeagueEntry]
+		if !ok {
+			return
+		}
+		row.PointsFor += card.Total
+		row.PointsAgainst += opp.Total
+		switch {
+		case card.Total > opp.Total:
+			row.Won++
+			row.Total += league.H2HWin
+		case card.Total < opp.Total:
+			row.Lost++
+			row.Total += league.H2HLose
+		default:
+			row.Drawn++
+			row.Total += league.H2HDraw
+		}
+	}
+	for _, m := range matchups {
+		if m.Finished || m.Home.Error != "" || m.Away.Error != "" {
+			continue
+		}
+		apply(m.Home, m.Away)
+		apply(m.Away, m.Home)
+	}
+
+	for i := range rows {
+		rows[i].LastRank = rows[i].Rank
+	}
+	rankStandings(rows)
+	for i := range rows {
+		rows[i].Movement = rows[i].LastRank - rows[i].Rank
+	}
+	return rows
+}
+
+// finishedUpTo returns the last gameweek up to gw with a finished
+// head-to-head, and how many times league entries played in those.
+func finishedUpTo(matches fpl.Matches, gw int) (upTo, played int) {
+	for _, m := range matches {
+		if !m.Finished || m.Event > gw {
+			continue
+		}
+		if m.Event > upTo {
+			upTo = m.Event
+		}
+		for _, entry := range []int{m.LeagueEntry1, m.LeagueEntry2} {
+			if entry != 0 {
+				played++
+			}
+		}
+	}
+	return upTo, played
+}
+
+// standingsAt rebuilds the table as it stood once gameweek gw ended from
+// the league's finished head-to-heads, since the API only serves today's
+// standings. LastRank and Movement compare with the gameweek before.
+func standingsAt(draft fpl.Draft, gw int, league fpl.LeagueSettings) []StandingRow {
+	table := func(upTo int) []StandingRow {
+		var rows []StandingRow
+		byEntry := map[int]int{}
+		for _, user := range draft.LeagueEntries {
+			byEntry[user.ID] = len(rows)
+			rows = append(rows, StandingRow{LeagueEntry: user.ID, Manager: user.PlayerFirstName})
+		}
+		apply := func(entry, pf, pa int) {
+			i, ok := byEntry[entry]
+			if !ok {
+				return
+			}
+			row := &rows[i]
+			row.PointsFor += pf
+			row.PointsAgainst += pa
+			switch {
+			case pf > pa:
+				row.Won++
+				row.Total += league.H2HWin
+			case pf < pa:
+				row.Lost++
+				row.Total += league.H2HLose
+			default:
+				row.Drawn++
+				row.Total += league.H2HDraw
+			}
+		}
+		for _, m := range draft.Matches {
+			if !m.Finished || m.Event > upTo {
+				continue
+			}
+			apply(m.LeagueEntry1, m.LeagueEntry1Points, m.LeagueEntry2Points)
+			apply(m.LeagueEntry2, m.LeagueEntry2Points, m.LeagueEntry1Points)
+		}
+		rankStandings(rows)
+		return rows
+	}
+
+	rows := table(gw)
+	if gw > draft.League.StartEvent {
+		before := map[int]int{}
+		for _, r := range table(gw - 1) {
+			before[r.LeagueEntry] = r.Rank
+		}
+		for i := range rows {
+			rows[i].LastRank = before[rows[i].LeagueEntry]
+			rows[i].Movement = rows[i].LastRank - rows[i].Rank
+		}
+	}
+	return rows
+}
@@ -0,0 +1,203 @@
+package dashboard
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// tradeStates labels the API's trade states.
+var tradeStates = map[string]string{
+	"o": "Offered",
+	"a": "Accepted",
+	"p": "Processed",
+	"v": "Vetoed",
+	"r": "Rejected",
+	"w": "Withdrawn",
+	"e": "Expired",
+	"i": "Invalid",
+}
+
+// TradeRow is one trade between two managers.
+type TradeRow struct {
+	ID            int       `json:"id"`
+	Gameweek      int       `json:"gameweek"`
+	OfferedEntry  int       `json:"offered_entry"`
+	Offered       string    `json:"offered"`
+	ReceivedEntry int       `json:"received_entry"`
+	Received      string    `json:"received"`
+	OfferedGets   []string  `json:"offered_gets"`
+	ReceivedGets  []string  `json:"received_gets"`
+	State         string    `json:"state"`
+	OfferTime     time.Time `json:"offer_time"`
+	// Pending is set while the trade awaits an answer or the veto window.
+	Pending   bool `json:"pending"`
+	Processed bool `json:"processed"`
+	// VetoEnds is when an accepted trade goes through unless vetoed.
+	VetoEnds *time.Time `json:"veto_ends,omitempty"`
+	// OfferedGain and ReceivedGain are the points each side's incoming
+	// players have scored since the trade took effect, less those of the
+	// players it gave away. Only the trades page computes them, and
+	// GainUnknown is set instead when a gameweek's live data failed to
+	// load.
+	OfferedGain  int  `json:"offered_gain"`
+	ReceivedGain int  `json:"received_gain"`
+	GainUnknown  bool `json:"gain_unknown"`
+
+	items []tradeItem
+}
+
+// tradeItem is one swap of a trade, from the offering side.
+type tradeItem struct {
+	in, out int
+}
+
+// TradesView is every trade of a league's season, newest first.
+type TradesView struct {
+	LeagueID   int    `json:"league_id"`
+	LeagueName string `json:"league_name"`
+	// ApprovalOpen is set while the game is holding trades for approval.
+	ApprovalOpen bool       `json:"approval_open"`
+	VetoHours    int        `json:"veto_hours"`
+	VetoMinimum  int        `json:"veto_minimum"`
+	Trades       []TradeRow `json:"trades"`
+}
+
+// tradeRows labels the trades of gw plus any still pending, or every
+// trade when gw is 0, newest first.
+func tradeRows(trades []fpl.Trade, gw int, draft fpl.Draft, players map[uint16]fpl.Player, rules fpl.TransactionSettings) []TradeRow {
+	leagueEntries, owners := entryOwners(draft)
+
+	var rows []TradeRow
+	for _, t := range trades {
+		pending := t.State == "o" || t.State == "a"
+		if gw != 0 && t.Event != gw && !pending {
+			continue
+		}
+		row := TradeRow{
+			ID:            t.ID,
+			Gameweek:      t.Event,
+			OfferedEntry:  leagueEntries[t.OfferedEntry],
+			Offered:       owners[leagueEntries[t.OfferedEntry]],
+			ReceivedEntry: leagueEntries[t.ReceivedEntry],
+			Received:      owners[leagueEntries[t.ReceivedEntry]],
+			State:         tradeStates[t.State],
+			OfferTime:     t.OfferTime,
+			Pending:       pending,
+			Processed:     t.State == "p",
+		}
+		if row.State == "" {
+			row.State = t.State
+		}
+		for _, item := range t.TradeItemSet {
+			row.items = append(row.items, tradeItem{in: item.ElementIn, out: item.ElementOut})
+			row.OfferedGets = append(row.OfferedGets, players[uint16(item.ElementIn)].WebName)
+			row.ReceivedGets = append(row.ReceivedGets, players[uint16(item.ElementOut)].WebName)
+		}
+		if t.State == "a" && t.ResponseTime != nil && rules.TradeVetoHours > 0 {
+			ends := t.ResponseTime.Add(time.Duration(rules.TradeVetoHours) * time.Hour)
+			row.VetoEnds = &ends
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].ID > rows[j].ID
+	})
+	return rows
+}
+
+// BuildTrades loads a league's trades and, for processed ones, the
+// points gained by each side in every gameweek from the one the trade
+// took effect up to the current one.
+func BuildTrades(ctx context.Context, src fpl.Source, leagueID int) (TradesView, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchDeadline)
+	defer cancel()
+
+	game, err := src.Game(ctx)
+	if err != nil {
+		return TradesView{}, err
+	}
+	draft, err := src.LeagueDetails(ctx, leagueID)
+	if err != nil {
+		return TradesView{}, err
+	}
+	bootstrap, err := src.Bootstrap(ctx)
+	if err != nil {
+		return TradesView{}, err
+	}
+	trades, err := src.Trades(ctx, leagueID)
+	if err != nil {
+		return TradesView{}, err
+	}
+
+	players := map[uint16]fpl.Player{}
+	for _, pl := range bootstrap.Players {
+		players[uint16(pl.ID)] = pl
+	}
+	rules := bootstrap.Settings.Transactions
+	view := TradesView{
+		LeagueID:     leagueID,
+		LeagueName:   draft.League.Name,
+		ApprovalOpen: game.TradesTimeForApproval,
+		VetoHours:    rules.TradeVetoHours,
+		VetoMinimum:  rules.TradeVetoMinimum,
+		Trades:       tradeRows(trades.Trades, 0, draft, players, rules),
+	}
+
+	// Load every gameweek a processed trade has counted in, in parallel.
+	first := int(game.CurrentEvent) + 1
+	for _, row := range view.Trades {
+		if row.Processed && row.Gameweek > 0 && row.Gameweek < first {
+			first = row.Gameweek
+		}
+	}
+	lives := map[int]fpl.Live{}
+	liveErrs := map[int]error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, fetchConcurrency)
+	for gw := first; gw <= int(game.CurrentEvent); gw++ {
+		gw := gw
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			live, err := src.Live(ctx, uint8(gw))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				liveErrs[gw] = err
+				return
+			}
+			lives[gw] = live
+		}()
+	}
+	wg.Wait()
+
+	for i := range view.Trades {
+		row := &view.Trades[i]
+		if !row.Processed {
+			continue
+		}
+		gain := 0
+		for gw := row.Gameweek; gw > 0 && gw <= int(game.CurrentEvent); gw++ {
+			if err, ok := liveErrs[gw]; ok {
+				log.Printf("league %d trade %d: live gw %d: %v", leagueID, row.ID, gw, err)
+				row.GainUnknown = true
+				break
+			}
+			for _, item := range row.items {
+				gain += lives[gw].El[uint16(item.in)].Stats.TotalPoints - lives[gw].El[uint16(item.out)].Stats.TotalPoints
+			}
+		}
+		if !row.GainUnknown {
+			row.OfferedGain, row.ReceivedGain = gain, -gain
+		}
+	}
+	return view, nil
+}
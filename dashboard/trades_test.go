@@ -0,0 +1,66 @@
+package dashboard_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"draft.kparajuli.com/m/dashboard"
+	"draft.kparajuli.com/m/fpl"
+	"draft.kparajuli.com/m/fpl/fpltest"
+)
+
+// failingLive fails every live request, as during an API outage.
+type failingLive struct {
+	fpl.Source
+}
+
+func (failingLive) Live(ctx context.Context, gw uint8) (fpl.Live, error) {
+	return fpl.Live{}, errors.New("503 Service Unavailable")
+}
+
+func TestBuildTrades(t *testing.T) {
+	src := fpltest.NewSource(t, "../fpl/testdata")
+
+	view, err := dashboard.BuildTrades(context.Background(), src, testLeague)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.VetoHours != 24 || view.VetoMinimum != 50 || len(view.Trades) != 3 {
+		t.Fatalf("veto %dh at %d%%, %d trades", view.VetoHours, view.VetoMinimum, len(view.Trades))
+	}
+	for _, row := range view.Trades {
+		switch row.ID {
+		case 71:
+			// Ødegaard (2 points) for J.Ayew (6 points) in gameweek 15.
+			if !row.Processed || row.GainUnknown || row.OfferedGain != -4 || row.ReceivedGain != 4 {
+				t.Errorf("processed trade: %+v", row)
+			}
+		case 72:
+			// Accepted at noon on December 9th, with a 24 hour veto window.
+			ends := time.Date(2023, 12, 10, 12, 0, 0, 0, time.UTC)
+			if !row.Pending || row.VetoEnds == nil || !row.VetoEnds.Equal(ends) {
+				t.Errorf("accepted trade: %+v", row)
+			}
+		case 73:
+			if row.Pending || row.Processed || row.State != "Rejected" {
+				t.Errorf("rejected trade: %+v", row)
+			}
+		}
+	}
+}
+
+func TestBuildTradesLiveUnavailable(t *testing.T) {
+	src := failingLive{fpltest.NewSource(t, "../fpl/testdata")}
+
+	view, err := dashboard.BuildTrades(context.Background(), src, testLeague)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range view.Trades {
+		if row.Processed && (!row.GainUnknown || row.OfferedGain != 0) {
+			t.Errorf("trade %d: gain %d, unknown %v", row.ID, row.OfferedGain, row.GainUnknown)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package dashboard
+
+import (
+	"context"
+	"sort"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// transactionKinds and transactionResults label the API's codes.
+var transactionKinds = map[string]string{
+	"w": "Waiver",
+	"f": "Free agent",
+}
+
+var transactionResults = map[string]string{
+	"a":  "Accepted",
+	"di": "Denied: invalid",
+	"do": "Denied: outbid",
+}
+
+// TransactionRow is one waiver claim or free-agent swap.
+type TransactionRow struct {
+	ID          int    `json:"id"`
+	Gameweek    int    `json:"gameweek"`
+	LeagueEntry int    `json:"league_entry"`
+	Manager     string `json:"manager"`
+	Kind        string `json:"kind"`
+	// Priority is the claim's place in the manager's own waiver list.
+	Priority  int    `json:"priority"`
+	In        string `json:"in"`
+	InTeam    string `json:"in_team"`
+	Out       string `json:"out"`
+	OutTeam   string `json:"out_team"`
+	Result    string `json:"result"`
+	Succeeded bool   `json:"succeeded"`
+}
+
+// WaiverRow is a manager's place in the waiver order; pick 1 claims first.
+type WaiverRow struct {
+	Pick        int    `json:"pick"`
+	LeagueEntry int    `json:"league_entry"`
+	Manager     string `json:"manager"`
+}
+
+// ManagerTransactions is one manager's season of roster moves.
+type ManagerTransactions struct {
+	LeagueEntry  int              `json:"league_entry"`
+	Manager      string           `json:"manager"`
+	WaiversWon   int              `json:"waivers_won"`
+	WaiversLost  int              `json:"waivers_lost"`
+	FreeAgents   int              `json:"free_agents"`
+	Transactions []TransactionRow `json:"transactions"`
+}
+
+// TransactionHistory is every transaction of a league's season, newest
+// first, grouped by manager in waiver order.
+type TransactionHistory struct {
+	LeagueID    int                   `json:"league_id"`
+	LeagueName  string                `json:"league_name"`
+	WaiverOrder []WaiverRow           `json:"waiver_order"`
+	Managers    []ManagerTransactions `json:"managers"`
+}
+
+// transactionRows labels the transactions of gw, or of the whole season
+// when gw is 0, in the order they were processed.
+func transactionRows(txs []fpl.Transaction, gw int, draft fpl.Draft, players map[uint16]fpl.Player, names labels) []TransactionRow {
+	leagueEntries, owners := entryOwners(draft)
+
+	var rows []TransactionRow
+	for _, tx := range txs {
+		if gw != 0 && tx.Event != gw {
+			continue
+		}
+		in, out := players[uint16(tx.ElementIn)], players[uint16(tx.ElementOut)]
+		row := TransactionRow{
+			ID:          tx.ID,
+			Gameweek:    tx.Event,
+			LeagueEntry: leagueEntries[tx.Entry],
+			Manager:     owners[leagueEntries[tx.Entry]],
+			Kind:        transactionKinds[tx.Kind],
+			Priority:    tx.Priority,
+			In:          in.WebName,
+			InTeam:      names.team(in.Team),
+			Out:         out.WebName,
+			OutTeam:     names.team(out.Team),
+			Result:      transactionResults[tx.Result],
+			Succeeded:   tx.Result == "a",
+		}
+		if row.Kind == "" {
+			row.Kind = tx.Kind
+		}
+		if row.Result == "" {
+			row.Result = tx.Result
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].ID < rows[j].ID
+	})
+	return rows
+}
+
+// entryOwners maps entry IDs, which transactions and trades use, to
+// league entries, and league entries to their managers.
+func entryOwners(draft fpl.Draft) (leagueEntries map[int]int, owners map[int]string) {
+	leagueEntries, owners = map[int]int{}, map[int]string{}
+	for _, user := range draft.LeagueEntries {
+		leagueEntries[user.EntryID] = user.ID
+		owners[user.ID] = user.PlayerFirstName
+	}
+	return leagueEntries, owners
+}
+
+// waiverOrder lists the league's managers by waiver pick.
+func waiverOrder(draft fpl.Draft) []WaiverRow {
+	var order []WaiverRow
+	for _, user := range draft.LeagueEntries {
+		order = append(order, WaiverRow{
+			Pick:        user.WaiverPick,
+			LeagueEntry: user.ID,
+			Manager:     user.PlayerFirstName,
+		})
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].Pick < order[j].Pick
+	})
+	return order
+}
+
+// BuildTransactions loads a league's season of transactions, which the
+// Source may serve from its archive once the API no longer has them.
+func BuildTransactions(ctx context.Context, src fpl.Source, leagueID int) (TransactionHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchDeadline)
+	defer cancel()
+
+	draft, err := src.LeagueDetails(ctx, leagueID)
+	if err != nil {
+		return TransactionHistory{}, err
+	}
+	bootstrap, err := src.Bootstrap(ctx)
+	if err != nil {
+		return TransactionHistory{}, err
+	}
+	txs, err := src.Transactions(ctx, leagueID)
+	if err != nil {
+		return TransactionHistory{}, err
+	}
+
+	players := map[uint16]fpl.Player{}
+	for _, pl := range bootstrap.Players {
+		players[uint16(pl.ID)] = pl
+	}
+	rows := transactionRows(txs.Transactions, 0, draft, players, newLabels(bootstrap))
+
+	history := TransactionHistory{
+		LeagueID:    leagueID,
+		LeagueName:  draft.League.Name,
+		WaiverOrder: waiverOrder(draft),
+	}
+	for _, w := range history.WaiverOrder {
+		m := ManagerTransactions{LeagueEntry: w.LeagueEntry, Manager: w.Manager}
+		for i := len(rows) - 1; i >= 0; i-- {
+			row := rows[i]
+			if row.LeagueEntry != w.LeagueEntry {
+				continue
+			}
+			switch {
+			case row.Kind != transactionKinds["w"]:
+				m.FreeAgents++
+			case row.Succeeded:
+				m.WaiversWon++
+			default:
+				m.WaiversLost++
+			}
+			m.Transactions = append(m.Transactions, row)
+		}
+		history.Managers = append(history.Managers, m)
+	}
+	return history, nil
+}
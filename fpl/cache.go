@@ -0,0 +1,312 @@
+package fpl
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CacheTTLs sets how long each endpoint's response is reused.
+type CacheTTLs struct {
+	Game         time.Duration
+	League       time.Duration
+	Bootstrap    time.Duration
+	LiveActive   time.Duration // live data while a fixture is in progress
+	LiveIdle     time.Duration // live data when nothing is being played
+	Fixtures     time.Duration
+	Picks        time.Duration // picks when the next deadline is unknown
+	Transactions time.Duration
+	Trades       time.Duration
+	Ownership    time.Duration // element status: who owns each player
+}
+
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		Game:         time.Minute,
+		League:       5 * time.Minute,
+		Bootstrap:    time.Hour,
+		LiveActive:   45 * time.Second,
+		LiveIdle:     10 * time.Minute,
+		Fixtures:     10 * time.Minute,
+		Picks:        10 * time.Minute,
+		Transactions: 5 * time.Minute,
+		Trades:       5 * time.Minute,
+		Ownership:    5 * time.Minute,
+	}
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// flight is an upstream request that concurrent misses on the same key
+// wait for instead of each going upstream.
+type flight struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// loadTimeout bounds an upstream request shared by concurrent misses,
+// retries included.
+const loadTimeout = 30 * time.Second
+
+// sweepInterval is how often store drops expired entries that nothing
+// has asked for again, such as past gameweeks loaded by a backfill.
+const sweepInterval = time.Minute
+
+// CacheStats counts cache hits and misses for one endpoint.
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// CachedSource sits in front of another Source and reuses responses
+// until their endpoint's TTL runs out. Errors are never cached, and
+// concurrent misses on the same key share one upstream request.
+type CachedSource struct {
+	src Source
+	ttl CacheTTLs
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	inflight  map[string]*flight
+	stats     map[string]*CacheStats
+	nextSweep time.Time
+}
+
+func NewCachedSource(src Source, ttl CacheTTLs) *CachedSource {
+	return &CachedSource{
+		src:      src,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]cacheEntry{},
+		inflight: map[string]*flight{},
+		stats:    map[string]*CacheStats{},
+	}
+}
+
+// get returns the cached value for key or loads it. load returns the
+// value and how long to keep it. Expired entries are dropped on the way.
+//
+// Concurrent misses share one load, which runs on its own context bounded
+// by loadTimeout rather than any caller's: one visitor going away must not
+// fail the request for everyone else. Each caller only stops waiting when
+// its own ctx is done.
+func (c *CachedSource) get(ctx context.Context, endpoint, key string, load func(ctx context.Context) (interface{}, time.Duration, error)) (interface{}, error) {
+	c.mu.Lock()
+	st, ok := c.stats[endpoint]
+	if !ok {
+		st = &CacheStats{}
+		c.stats[endpoint] = st
+	}
+	if entry, ok := c.entries[key]; ok {
+		if c.now().Before(entry.expires) {
+			st.Hits++
+			c.mu.Unlock()
+			return entry.value, nil
+		}
+		delete(c.entries, key)
+	}
+	st.Misses++
+	f, ok := c.inflight[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		c.inflight[key] = f
+		go c.load(key, f, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load runs a shared upstream request for key and stores its result.
+func (c *CachedSource) load(key string, f *flight, load func(ctx context.Context) (interface{}, time.Duration, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+	defer cancel()
+
+	var ttl time.Duration
+	f.value, ttl, f.err = load(ctx)
+	if f.err == nil {
+		c.store(key, f.value, ttl)
+	}
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(f.done)
+}
+
+func (c *CachedSource) store(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(ttl)}
+	if now.Before(c.nextSweep) {
+		return
+	}
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.nextSweep = now.Add(sweepInterval)
+}
+
+// peek returns a cached value without touching the counters, even if it
+// has expired but not yet been dropped. It is used to pick TTLs from data
+// we already hold.
+func (c *CachedSource) peek(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry.value, ok
+}
+
+// Stats returns a copy of the per-endpoint hit/miss counters.
+func (c *CachedSource) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := map[string]CacheStats{}
+	for endpoint, st := range c.stats {
+		out[endpoint] = *st
+	}
+	return out
+}
+
+// inProgress reports whether fixtures contain a game that has kicked off
+// but not finished.
+func inProgress(fixtures Fixtures) bool {
+	for _, f := range fixtures {
+		if f.Started && !f.Finished {
+			return true
+		}
+	}
+	return false
+}
+
+// picksTTL keeps a squad until the deadline of the gameweek after gw,
+// which is the earliest moment it can change. Squads of past gameweeks
+// never change and are kept for a day.
+func (c *CachedSource) picksTTL(gw uint8) time.Duration {
+	v, ok := c.peek("bootstrap")
+	if !ok {
+		return c.ttl.Picks
+	}
+	for _, ev := range v.(Bootstrap).Events.Data {
+		if ev.ID == int(gw)+1 {
+			until := ev.DeadlineTime.Sub(c.now())
+			if until < 0 {
+				return 24 * time.Hour
+			}
+			if until > c.ttl.Picks {
+				return until
+			}
+		}
+	}
+	return c.ttl.Picks
+}
+
+func (c *CachedSource) Game(ctx context.Context) (Game, error) {
+	v, err := c.get(ctx, "game", "game", func(ctx context.Context) (interface{}, time.Duration, error) {
+		game, err := c.src.Game(ctx)
+		return game, c.ttl.Game, err
+	})
+	game, _ := v.(Game)
+	return game, err
+}
+
+func (c *CachedSource) LeagueDetails(ctx context.Context, leagueID int) (Draft, error) {
+	key := "league/" + strconv.Itoa(leagueID)
+	v, err := c.get(ctx, "league", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		draft, err := c.src.LeagueDetails(ctx, leagueID)
+		return draft, c.ttl.League, err
+	})
+	draft, _ := v.(Draft)
+	return draft, err
+}
+
+func (c *CachedSource) Live(ctx context.Context, gw uint8) (Live, error) {
+	key := "live/" + strconv.Itoa(int(gw))
+	v, err := c.get(ctx, "live", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		live, err := c.src.Live(ctx, gw)
+		ttl := c.ttl.LiveIdle
+		if fixtures, ok := c.peek("fixtures/" + strconv.Itoa(int(gw))); ok && inProgress(fixtures.(Fixtures)) {
+			ttl = c.ttl.LiveActive
+		}
+		return live, ttl, err
+	})
+	live, _ := v.(Live)
+	return live, err
+}
+
+func (c *CachedSource) EntryPicks(ctx context.Context, entry uint32, gw uint8) (Club, error) {
+	key := "picks/" + strconv.Itoa(int(entry)) + "/" + strconv.Itoa(int(gw))
+	v, err := c.get(ctx, "picks", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		club, err := c.src.EntryPicks(ctx, entry, gw)
+		return club, c.picksTTL(gw), err
+	})
+	club, _ := v.(Club)
+	return club, err
+}
+
+func (c *CachedSource) Bootstrap(ctx context.Context) (Bootstrap, error) {
+	v, err := c.get(ctx, "bootstrap", "bootstrap", func(ctx context.Context) (interface{}, time.Duration, error) {
+		bootstrap, err := c.src.Bootstrap(ctx)
+		return bootstrap, c.ttl.Bootstrap, err
+	})
+	bootstrap, _ := v.(Bootstrap)
+	return bootstrap, err
+}
+
+func (c *CachedSource) Fixtures(ctx context.Context, gw uint8) (Fixtures, error) {
+	key := "fixtures/" + strconv.Itoa(int(gw))
+	v, err := c.get(ctx, "fixtures", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		fixtures, err := c.src.Fixtures(ctx, gw)
+		ttl := c.ttl.Fixtures
+		if inProgress(fixtures) {
+			ttl = c.ttl.LiveActive
+		}
+		return fixtures, ttl, err
+	})
+	fixtures, _ := v.(Fixtures)
+	return fixtures, err
+}
+
+func (c *CachedSource) Transactions(ctx context.Context, leagueID int) (Transactions, error) {
+	key := "transactions/" + strconv.Itoa(leagueID)
+	v, err := c.get(ctx, "transactions", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		txs, err := c.src.Transactions(ctx, leagueID)
+		return txs, c.ttl.Transactions, err
+	})
+	txs, _ := v.(Transactions)
+	return txs, err
+}
+
+func (c *CachedSource) Trades(ctx context.Context, leagueID int) (Trades, error) {
+	key := "trades/" + strconv.Itoa(leagueID)
+	v, err := c.get(ctx, "trades", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		trades, err := c.src.Trades(ctx, leagueID)
+		return trades, c.ttl.Trades, err
+	})
+	trades, _ := v.(Trades)
+	return trades, err
+}
+
+func (c *CachedSource) ElementStatus(ctx context.Context, leagueID int) (ElementStatuses, error) {
+	key := "element-status/" + strconv.Itoa(leagueID)
+	v, err := c.get(ctx, "element-status", key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		statuses, err := c.src.ElementStatus(ctx, leagueID)
+		return statuses, c.ttl.Ownership, err
+	})
+	statuses, _ := v.(ElementStatuses)
+	return statuses, err
+}
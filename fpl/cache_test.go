@@ -0,0 +1,123 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowSource counts Game and Live calls and holds each Game call until
+// release is closed, failing it if its context was canceled meanwhile.
+type slowSource struct {
+	Source
+	release chan struct{}
+	games   int32
+	lives   int32
+}
+
+func (s *slowSource) Game(ctx context.Context) (Game, error) {
+	atomic.AddInt32(&s.games, 1)
+	<-s.release
+	if err := ctx.Err(); err != nil {
+		return Game{}, err
+	}
+	return Game{CurrentEvent: 15}, nil
+}
+
+func (s *slowSource) Live(ctx context.Context, gw uint8) (Live, error) {
+	atomic.AddInt32(&s.lives, 1)
+	return Live{}, nil
+}
+
+func TestCachedSourceCoalescesMisses(t *testing.T) {
+	src := &slowSource{release: make(chan struct{})}
+	c := NewCachedSource(src, DefaultCacheTTLs())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			game, err := c.Game(context.Background())
+			if err != nil || game.CurrentEvent != 15 {
+				t.Errorf("Game() = %+v, %v", game, err)
+			}
+		}()
+	}
+	// Let the callers pile up behind the first request.
+	time.Sleep(50 * time.Millisecond)
+	close(src.release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&src.games); n != 1 {
+		t.Errorf("upstream Game calls = %d, want 1", n)
+	}
+}
+
+func TestCachedSourceCallerCancelDoesNotFailOthers(t *testing.T) {
+	src := &slowSource{release: make(chan struct{})}
+	c := NewCachedSource(src, DefaultCacheTTLs())
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := c.Game(first)
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan Game)
+	go func() {
+		game, err := c.Game(context.Background())
+		if err != nil {
+			t.Errorf("second caller: %v", err)
+		}
+		second <- game
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller err = %v, want context.Canceled", err)
+	}
+	close(src.release)
+	if game := <-second; game.CurrentEvent != 15 {
+		t.Errorf("second caller got %+v", game)
+	}
+	if n := atomic.LoadInt32(&src.games); n != 1 {
+		t.Errorf("upstream Game calls = %d, want 1", n)
+	}
+	if game, err := c.Game(context.Background()); err != nil || game.CurrentEvent != 15 {
+		t.Errorf("cached Game() = %+v, %v", game, err)
+	}
+}
+
+func TestCachedSourceDropsExpiredEntries(t *testing.T) {
+	src := &slowSource{release: make(chan struct{})}
+	close(src.release)
+	c := NewCachedSource(src, DefaultCacheTTLs())
+	now := time.Date(2023, 12, 9, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for gw := uint8(1); gw <= 15; gw++ {
+		c.Live(ctx, gw)
+	}
+	if len(c.entries) != 15 {
+		t.Fatalf("entries = %d, want 15", len(c.entries))
+	}
+
+	now = now.Add(time.Hour)
+	c.Game(ctx)
+	if len(c.entries) != 1 {
+		t.Errorf("entries after expiry = %d, want only the game", len(c.entries))
+	}
+
+	c.Live(ctx, 1)
+	if n := atomic.LoadInt32(&src.lives); n != 16 {
+		t.Errorf("upstream Live calls = %d, want 16", n)
+	}
+}
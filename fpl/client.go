@@ -0,0 +1,158 @@
+// Package fpl is a client for the Fantasy Premier League draft API at
+// draft.premierleague.com: the response types, an HTTP client with retries,
+// and Sources that serve the same payloads from captures or a cache.
+package fpl
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultUserAgent = "draftee/1.0 (+https://draftee.kparajuli.com)"
+
+// ErrNotFound matches any APIError with a 404 status via errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned when the draft API answers with a non-2xx status.
+type APIError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	return "GET " + e.URL + ": " + e.Status
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// retryable reports whether the request is worth repeating: the server
+// was overloaded (5xx) or asked us to slow down (429).
+func (e *APIError) retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// DecodeError is returned when a response body is not the JSON we expect.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return "decode " + e.URL + ": " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Client is the one HTTP client shared by every draft API fetcher.
+// Each attempt gets its own timeout, failed attempts are retried with
+// exponential backoff and responses are transparently gunzipped.
+type Client struct {
+	// HTTP sends the requests. Swap its Transport to record or stub them.
+	HTTP      *http.Client
+	baseURL   string
+	userAgent string
+	timeout   time.Duration
+	retries   int
+	backoff   time.Duration
+}
+
+// NewClient returns a Client for the API at baseURL, normally BaseURL.
+func NewClient(baseURL, userAgent string) *Client {
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	return &Client{
+		HTTP:      &http.Client{},
+		baseURL:   baseURL,
+		userAgent: userAgent,
+		timeout:   10 * time.Second,
+		retries:   3,
+		backoff:   500 * time.Millisecond,
+	}
+}
+
+// GetJSON fetches path below the base URL and decodes it into v.
+func (c *Client) GetJSON(ctx context.Context, path string, v interface{}) error {
+	for attempt := 0; ; attempt++ {
+		wait, err := c.try(ctx, path, v)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt >= c.retries || ctx.Err() != nil {
+			return err
+		}
+
+		if wait == 0 {
+			wait = c.backoff << attempt
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// try performs a single attempt. The returned duration is negative when
+// the error is final, zero to use the default backoff, and positive when
+// the server told us how long to wait.
+func (c *Client) try(ctx context.Context, path string, v interface{}) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	url := c.baseURL + path
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		// Transport errors (resets, timeouts) are worth another go.
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+		if !apiErr.retryable() {
+			return -1, apiErr
+		}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second, apiErr
+		}
+		return 0, apiErr
+	}
+
+	var body io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return -1, &DecodeError{URL: url, Err: err}
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return -1, &DecodeError{URL: url, Err: err}
+	}
+	return 0, nil
+}
@@ -0,0 +1,130 @@
+package fpl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"draft.kparajuli.com/m/fpl"
+	"draft.kparajuli.com/m/fpl/fpltest"
+)
+
+func TestClientGetJSON(t *testing.T) {
+	server := fpltest.NewStandIn("testdata")
+	defer server.Close()
+	client := fpl.NewClient(server.URL+"/api", "")
+
+	var game fpl.Game
+	if err := client.GetJSON(context.Background(), "/game", &game); err != nil {
+		t.Fatal(err)
+	}
+	if game.CurrentEvent != 15 || !game.CurrentEventFinished {
+		t.Errorf("game = %+v, want gameweek 15 finished", game)
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	server := fpltest.NewStandIn("testdata")
+	defer server.Close()
+	client := fpl.NewClient(server.URL+"/api", "")
+
+	var club fpl.Club
+	err := client.GetJSON(context.Background(), "/entry/1/event/15", &club)
+	if !errors.Is(err, fpl.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+	var apiErr *fpl.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("err = %#v, want a 404 APIError", err)
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	var calls int32
+	golden := fpl.GoldenHandler("testdata")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		golden(w, r)
+	}))
+	defer server.Close()
+	client := fpl.NewClient(server.URL+"/api", "")
+
+	var game fpl.Game
+	if err := client.GetJSON(context.Background(), "/game", &game); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+func TestClientDecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>maintenance</html>"))
+	}))
+	defer server.Close()
+	client := fpl.NewClient(server.URL+"/api", "")
+
+	var game fpl.Game
+	err := client.GetJSON(context.Background(), "/game", &game)
+	var decodeErr *fpl.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("err = %v, want a DecodeError", err)
+	}
+}
+
+// TestRecordTransport records through the stand-in, which gzips its
+// responses, and expects the golden files back byte for byte.
+func TestRecordTransport(t *testing.T) {
+	server := fpltest.NewStandIn("testdata")
+	defer server.Close()
+	dir := t.TempDir()
+	client := fpl.NewClient(server.URL+"/api", "")
+	client.HTTP.Transport = fpl.NewRecordTransport(client.HTTP.Transport, dir)
+
+	for _, path := range []string{"/game", "/league/29143/details", "/event/15/fixtures"} {
+		var v interface{}
+		if err := client.GetJSON(context.Background(), path, &v); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		name := fpl.GoldenName("/api" + path)
+		want, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s not recorded: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s recorded %d bytes, want %d", name, len(got), len(want))
+		}
+	}
+}
+
+func TestGoldenName(t *testing.T) {
+	tests := map[string]string{
+		"/api/event/1/live":                     "api-event-1-live.json",
+		"/api/league/29143/details":             "api-league-29143-details.json",
+		"/api/draft/league/29143/transactions/": "api-draft-league-29143-transactions.json",
+		"/":                                     "index.json",
+	}
+	for path, want := range tests {
+		if got := fpl.GoldenName(path); got != want {
+			t.Errorf("GoldenName(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if strings.Contains(fpl.GoldenName("/api/entry/1/event/2"), "/") {
+		t.Error("golden names must not contain slashes")
+	}
+}
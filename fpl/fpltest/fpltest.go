@@ -0,0 +1,26 @@
+// Package fpltest plays golden files back through a local stand-in for
+// draft.premierleague.com, so tests can run the real client and every
+// fetcher end to end. The recorded responses live in fpl/testdata.
+package fpltest
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// NewStandIn starts a stand-in serving the golden files in dir. Point a
+// Client at server.URL+"/api" to run it against the stand-in.
+func NewStandIn(dir string) *httptest.Server {
+	return httptest.NewServer(fpl.GoldenHandler(dir))
+}
+
+// NewSource returns an HTTPSource reading from a stand-in for dir that
+// is shut down when the test ends.
+func NewSource(t testing.TB, dir string) *fpl.HTTPSource {
+	t.Helper()
+	server := NewStandIn(dir)
+	t.Cleanup(server.Close)
+	return fpl.NewHTTPSource(fpl.NewClient(server.URL+"/api", ""))
+}
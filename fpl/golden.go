@@ -0,0 +1,100 @@
+package fpl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Golden files hold one upstream response each, named after the request
+// path: /api/event/1/live is stored as api-event-1-live.json. Recording
+// does what the curl-*.sh scripts did by hand for every endpoint, and
+// GoldenHandler plays the files back as a stand-in for
+// draft.premierleague.com, so the client and every fetcher can run
+// without network access. The tests play back testdata through fpltest.
+
+// GoldenName maps an API request path to its golden file name.
+func GoldenName(path string) string {
+	name := strings.ReplaceAll(strings.Trim(path, "/"), "/", "-")
+	if name == "" {
+		name = "index"
+	}
+	return name + ".json"
+}
+
+// RecordTransport passes requests on and saves every successful response
+// body, decompressed, as a golden file in dir.
+type RecordTransport struct {
+	next http.RoundTripper
+	dir  string
+}
+
+func NewRecordTransport(next http.RoundTripper, dir string) *RecordTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordTransport{next: next, dir: dir}
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	plain := body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err == nil {
+			plain, err = io.ReadAll(gz)
+		}
+		if err != nil {
+			log.Printf("golden %s: %v", req.URL.Path, err)
+			return resp, nil
+		}
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		log.Printf("golden %s: %v", req.URL.Path, err)
+		return resp, nil
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, GoldenName(req.URL.Path)), plain, 0o644); err != nil {
+		log.Printf("golden %s: %v", req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// GoldenHandler serves golden files from dir at the paths they were
+// recorded from, gzipped when the client asks for it. Paths without a
+// golden file get a 404, just like an unknown entry upstream.
+func GoldenHandler(dir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := os.ReadFile(filepath.Join(dir, GoldenName(r.URL.Path)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write(body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write(body)
+		gz.Close()
+	}
+}
@@ -0,0 +1,109 @@
+package fpl
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// Float is a number the API may send as a JSON string, e.g. "4.5" for a
+// player's form. It decodes either form, treats null and "" as 0, and
+// encodes as a plain number.
+type Float float64
+
+func (f *Float) UnmarshalJSON(b []byte) error {
+	b = bytes.Trim(b, `"`)
+	if len(b) == 0 || string(b) == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return err
+	}
+	*f = Float(v)
+	return nil
+}
+
+func (f Float) MarshalJSON() ([]byte, error) {
+	return strconv.AppendFloat(nil, float64(f), 'f', -1, 64), nil
+}
+
+// Player is a footballer from bootstrap-static with his season totals.
+// String-encoded numbers decode to Float; fields the API leaves null until
+// they apply, such as injury news or set-piece orders, are pointers.
+type Player struct {
+	ID          int    `json:"id"`
+	Code        int    `json:"code"`
+	ElementType int    `json:"element_type"`
+	Team        int    `json:"team"`
+	FirstName   string `json:"first_name"`
+	SecondName  string `json:"second_name"`
+	WebName     string `json:"web_name"`
+	SquadNumber *int   `json:"squad_number"`
+	// Status is "a" available, "d" doubtful, "i" injured, "s" suspended,
+	// "n" not available or "u" unavailable, e.g. left the league.
+	Status string    `json:"status"`
+	Added  time.Time `json:"added"`
+
+	News                     string     `json:"news"`
+	NewsAdded                *time.Time `json:"news_added"`
+	NewsReturn               *time.Time `json:"news_return"`
+	NewsUpdated              *time.Time `json:"news_updated"`
+	ChanceOfPlayingNextRound *int       `json:"chance_of_playing_next_round"`
+	ChanceOfPlayingThisRound *int       `json:"chance_of_playing_this_round"`
+
+	TotalPoints    int    `json:"total_points"`
+	EventPoints    int    `json:"event_points"`
+	PointsPerGame  Float  `json:"points_per_game"`
+	Form           Float  `json:"form"`
+	EpNext         *Float `json:"ep_next"`
+	EpThis         *Float `json:"ep_this"`
+	DraftRank      int    `json:"draft_rank"`
+	DreamteamCount int    `json:"dreamteam_count"`
+	InDreamteam    bool   `json:"in_dreamteam"`
+
+	Minutes         int `json:"minutes"`
+	Starts          int `json:"starts"`
+	GoalsScored     int `json:"goals_scored"`
+	Assists         int `json:"assists"`
+	CleanSheets     int `json:"clean_sheets"`
+	GoalsConceded   int `json:"goals_conceded"`
+	OwnGoals        int `json:"own_goals"`
+	PenaltiesSaved  int `json:"penalties_saved"`
+	PenaltiesMissed int `json:"penalties_missed"`
+	YellowCards     int `json:"yellow_cards"`
+	RedCards        int `json:"red_cards"`
+	Saves           int `json:"saves"`
+	Bonus           int `json:"bonus"`
+	Bps             int `json:"bps"`
+
+	Influence                Float `json:"influence"`
+	Creativity               Float `json:"creativity"`
+	Threat                   Float `json:"threat"`
+	IctIndex                 Float `json:"ict_index"`
+	ExpectedGoals            Float `json:"expected_goals"`
+	ExpectedAssists          Float `json:"expected_assists"`
+	ExpectedGoalInvolvements Float `json:"expected_goal_involvements"`
+	ExpectedGoalsConceded    Float `json:"expected_goals_conceded"`
+
+	InfluenceRank         int  `json:"influence_rank"`
+	InfluenceRankType     int  `json:"influence_rank_type"`
+	CreativityRank        int  `json:"creativity_rank"`
+	CreativityRankType    int  `json:"creativity_rank_type"`
+	ThreatRank            int  `json:"threat_rank"`
+	ThreatRankType        int  `json:"threat_rank_type"`
+	IctIndexRank          int  `json:"ict_index_rank"`
+	IctIndexRankType      int  `json:"ict_index_rank_type"`
+	FormRank              *int `json:"form_rank"`
+	FormRankType          *int `json:"form_rank_type"`
+	PointsPerGameRank     *int `json:"points_per_game_rank"`
+	PointsPerGameRankType *int `json:"points_per_game_rank_type"`
+
+	CornersAndIndirectFreekicksOrder *int   `json:"corners_and_indirect_freekicks_order"`
+	CornersAndIndirectFreekicksText  string `json:"corners_and_indirect_freekicks_text"`
+	DirectFreekicksOrder             *int   `json:"direct_freekicks_order"`
+	DirectFreekicksText              string `json:"direct_freekicks_text"`
+	PenaltiesOrder                   *int   `json:"penalties_order"`
+	PenaltiesText                    string `json:"penalties_text"`
+}
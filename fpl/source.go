@@ -0,0 +1,237 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Source provides the raw draft API payloads the dashboard is built
+// from, either live from draft.premierleague.com or from local captures.
+type Source interface {
+	Game(ctx context.Context) (Game, error)
+	LeagueDetails(ctx context.Context, leagueID int) (Draft, error)
+	Live(ctx context.Context, gw uint8) (Live, error)
+	EntryPicks(ctx context.Context, entry uint32, gw uint8) (Club, error)
+	Bootstrap(ctx context.Context) (Bootstrap, error)
+	Fixtures(ctx context.Context, gw uint8) (Fixtures, error)
+	Transactions(ctx context.Context, leagueID int) (Transactions, error)
+	Trades(ctx context.Context, leagueID int) (Trades, error)
+	ElementStatus(ctx context.Context, leagueID int) (ElementStatuses, error)
+}
+
+const BaseURL = "https://draft.premierleague.com/api"
+
+// HTTPSource fetches everything from the draft API.
+type HTTPSource struct {
+	client *Client
+}
+
+func NewHTTPSource(client *Client) *HTTPSource {
+	return &HTTPSource{client: client}
+}
+
+func (s *HTTPSource) Game(ctx context.Context) (Game, error) {
+	var game Game
+	err := s.client.GetJSON(ctx, "/game", &game)
+	return game, err
+}
+
+func (s *HTTPSource) LeagueDetails(ctx context.Context, leagueID int) (Draft, error) {
+	var draft Draft
+	err := s.client.GetJSON(ctx, "/league/"+strconv.Itoa(leagueID)+"/details", &draft)
+	return draft, err
+}
+
+func (s *HTTPSource) Live(ctx context.Context, gw uint8) (Live, error) {
+	var live Live
+	err := s.client.GetJSON(ctx, "/event/"+strconv.Itoa(int(gw))+"/live", &live)
+	return live, err
+}
+
+func (s *HTTPSource) EntryPicks(ctx context.Context, entry uint32, gw uint8) (Club, error) {
+	var club Club
+	err := s.client.GetJSON(ctx, "/entry/"+strconv.Itoa(int(entry))+"/event/"+strconv.Itoa(int(gw)), &club)
+	return club, err
+}
+
+func (s *HTTPSource) Bootstrap(ctx context.Context) (Bootstrap, error) {
+	var bootstrap Bootstrap
+	err := s.client.GetJSON(ctx, "/bootstrap-static", &bootstrap)
+	return bootstrap, err
+}
+
+func (s *HTTPSource) Fixtures(ctx context.Context, gw uint8) (Fixtures, error) {
+	var fixtures Fixtures
+	err := s.client.GetJSON(ctx, "/event/"+strconv.Itoa(int(gw))+"/fixtures", &fixtures)
+	return fixtures, err
+}
+
+func (s *HTTPSource) Transactions(ctx context.Context, leagueID int) (Transactions, error) {
+	var txs Transactions
+	err := s.client.GetJSON(ctx, "/draft/league/"+strconv.Itoa(leagueID)+"/transactions", &txs)
+	return txs, err
+}
+
+func (s *HTTPSource) Trades(ctx context.Context, leagueID int) (Trades, error) {
+	var trades Trades
+	err := s.client.GetJSON(ctx, "/draft/league/"+strconv.Itoa(leagueID)+"/trades", &trades)
+	return trades, err
+}
+
+func (s *HTTPSource) ElementStatus(ctx context.Context, leagueID int) (ElementStatuses, error) {
+	var statuses ElementStatuses
+	err := s.client.GetJSON(ctx, "/league/"+strconv.Itoa(leagueID)+"/element-status", &statuses)
+	return statuses, err
+}
+
+// FileSource reads captured API responses from a directory, so the
+// dashboard can run without network access. Each payload is looked up
+// under a specific name first (e.g. data-live-15.json) and then under the
+// generic capture name the curl scripts write (data-live.json).
+type FileSource struct {
+	dir string
+}
+
+func NewFileSource(dir string) *FileSource {
+	return &FileSource{dir: dir}
+}
+
+// ReadJSON decodes the first of names that exists in the directory.
+func (s *FileSource) ReadJSON(v interface{}, names ...string) error {
+	for _, name := range names {
+		file, err := os.Open(filepath.Join(s.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = json.NewDecoder(file).Decode(v)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: %w", names[0], os.ErrNotExist)
+}
+
+// Game reads data-game.json, falling back to the events block of the
+// bootstrap capture when no game state was recorded.
+func (s *FileSource) Game(ctx context.Context) (Game, error) {
+	var game Game
+	err := s.ReadJSON(&game, "data-game.json")
+	if !errors.Is(err, os.ErrNotExist) {
+		return game, err
+	}
+
+	bootstrap, err := s.Bootstrap(ctx)
+	if err != nil {
+		return game, err
+	}
+	game.CurrentEvent = uint8(bootstrap.Events.Current)
+	game.NextEvent = uint8(bootstrap.Events.Next)
+	for _, ev := range bootstrap.Events.Data {
+		if ev.ID == bootstrap.Events.Current {
+			game.CurrentEventFinished = ev.Finished
+		}
+	}
+	return game, nil
+}
+
+func (s *FileSource) LeagueDetails(ctx context.Context, leagueID int) (Draft, error) {
+	var draft Draft
+	err := s.ReadJSON(&draft,
+		"data-draft-league-"+strconv.Itoa(leagueID)+".json",
+		"data-draft-league.json")
+	return draft, err
+}
+
+// Live reads data-live-{gw}.json. The generic data-live.json carries no
+// gameweek, so it is only used for the game's current one.
+func (s *FileSource) Live(ctx context.Context, gw uint8) (Live, error) {
+	var live Live
+	name := "data-live-" + strconv.Itoa(int(gw)) + ".json"
+	err := s.ReadJSON(&live, name)
+	if !errors.Is(err, os.ErrNotExist) {
+		return live, err
+	}
+
+	game, err := s.Game(ctx)
+	if err != nil {
+		return live, err
+	}
+	if game.CurrentEvent != gw {
+		return live, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	err = s.ReadJSON(&live, "data-live.json")
+	return live, err
+}
+
+func (s *FileSource) EntryPicks(ctx context.Context, entry uint32, gw uint8) (Club, error) {
+	var club Club
+	err := s.ReadJSON(&club,
+		"data-entry-"+strconv.Itoa(int(entry))+"-"+strconv.Itoa(int(gw))+".json")
+	return club, err
+}
+
+func (s *FileSource) Bootstrap(ctx context.Context) (Bootstrap, error) {
+	var bootstrap Bootstrap
+	err := s.ReadJSON(&bootstrap, "data-bootstrap-static.json")
+	return bootstrap, err
+}
+
+// Fixtures reads data-fixtures-{gw}.json, falling back to the gameweek's
+// fixtures from the generic data-fixtures/data-fixtures.json capture.
+func (s *FileSource) Fixtures(ctx context.Context, gw uint8) (Fixtures, error) {
+	var fixtures Fixtures
+	name := "data-fixtures-" + strconv.Itoa(int(gw)) + ".json"
+	err := s.ReadJSON(&fixtures, name)
+	if !errors.Is(err, os.ErrNotExist) {
+		return fixtures, err
+	}
+
+	var all Fixtures
+	if err := s.ReadJSON(&all, filepath.Join("data-fixtures", "data-fixtures.json")); err != nil {
+		return nil, err
+	}
+	for _, f := range all {
+		if f.Event == int(gw) {
+			fixtures = append(fixtures, f)
+		}
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return fixtures, nil
+}
+
+func (s *FileSource) Transactions(ctx context.Context, leagueID int) (Transactions, error) {
+	var txs Transactions
+	err := s.ReadJSON(&txs,
+		"data-transactions-"+strconv.Itoa(leagueID)+".json",
+		"data-transactions.json")
+	return txs, err
+}
+
+func (s *FileSource) Trades(ctx context.Context, leagueID int) (Trades, error) {
+	var trades Trades
+	err := s.ReadJSON(&trades,
+		"data-trades-"+strconv.Itoa(leagueID)+".json",
+		"data-trades.json")
+	return trades, err
+}
+
+func (s *FileSource) ElementStatus(ctx context.Context, leagueID int) (ElementStatuses, error) {
+	var statuses ElementStatuses
+	err := s.ReadJSON(&statuses,
+		"data-element-status-"+strconv.Itoa(leagueID)+".json",
+		"data-element-status.json")
+	return statuses, err
+}
@@ -0,0 +1,109 @@
+package fpl_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"draft.kparajuli.com/m/fpl"
+	"draft.kparajuli.com/m/fpl/fpltest"
+)
+
+const testLeague = 29143
+
+// TestHTTPSource runs every Source method through the real client against
+// the golden files in testdata. The bootstrap, league, live and fixtures
+// are captures of gameweek 15 of 2023-24; the picks, transactions, trades
+// and element status are generated against them. The picks are valid
+// squads of real players but don't reproduce the official scores.
+func TestHTTPSource(t *testing.T) {
+	src := fpltest.NewSource(t, "testdata")
+	ctx := context.Background()
+
+	game, err := src.Game(ctx)
+	if err != nil || game.CurrentEvent != 15 {
+		t.Errorf("Game() = %+v, %v", game, err)
+	}
+
+	draft, err := src.LeagueDetails(ctx, testLeague)
+	if err != nil {
+		t.Fatalf("LeagueDetails(): %v", err)
+	}
+	if draft.League.ID != testLeague || len(draft.LeagueEntries) != 6 || len(draft.Standings) != 6 {
+		t.Errorf("LeagueDetails() = league %d with %d entries, %d standings", draft.League.ID, len(draft.LeagueEntries), len(draft.Standings))
+	}
+
+	bootstrap, err := src.Bootstrap(ctx)
+	if err != nil {
+		t.Fatalf("Bootstrap(): %v", err)
+	}
+	if len(bootstrap.Players) != 757 || len(bootstrap.Teams) != 20 || bootstrap.Settings.Squad.Play != 11 {
+		t.Errorf("Bootstrap() = %d players, %d teams, %d to play", len(bootstrap.Players), len(bootstrap.Teams), bootstrap.Settings.Squad.Play)
+	}
+
+	live, err := src.Live(ctx, 15)
+	if err != nil || len(live.El) == 0 {
+		t.Errorf("Live(15) = %d elements, %v", len(live.El), err)
+	}
+	if saka := live.El[19].Stats; saka.TotalPoints != 10 || saka.Bonus != 3 {
+		t.Errorf("Live(15) Saka = %d points, %d bonus", saka.TotalPoints, saka.Bonus)
+	}
+
+	fixtures, err := src.Fixtures(ctx, 15)
+	if err != nil || len(fixtures) != 10 {
+		t.Errorf("Fixtures(15) = %d fixtures, %v", len(fixtures), err)
+	}
+	for _, f := range fixtures {
+		if f.Event != 15 || !f.Finished {
+			t.Errorf("fixture %d: event %d, finished %v", f.ID, f.Event, f.Finished)
+		}
+	}
+
+	for _, user := range draft.LeagueEntries {
+		club, err := src.EntryPicks(ctx, uint32(user.EntryID), 15)
+		if err != nil || len(club.Squad) != 15 {
+			t.Errorf("EntryPicks(%d, 15) = %d picks, %v", user.EntryID, len(club.Squad), err)
+		}
+	}
+
+	txs, err := src.Transactions(ctx, testLeague)
+	if err != nil || len(txs.Transactions) != 4 {
+		t.Errorf("Transactions() = %d, %v", len(txs.Transactions), err)
+	}
+
+	trades, err := src.Trades(ctx, testLeague)
+	if err != nil || len(trades.Trades) != 3 {
+		t.Errorf("Trades() = %d, %v", len(trades.Trades), err)
+	}
+	for _, tr := range trades.Trades {
+		if len(tr.TradeItemSet) == 0 || tr.ResponseTime == nil {
+			t.Errorf("trade %d: %d items, response %v", tr.ID, len(tr.TradeItemSet), tr.ResponseTime)
+		}
+	}
+
+	statuses, err := src.ElementStatus(ctx, testLeague)
+	if err != nil || len(statuses.ElementStatus) != len(bootstrap.Players) {
+		t.Errorf("ElementStatus() = %d, %v", len(statuses.ElementStatus), err)
+	}
+	owned := 0
+	for _, st := range statuses.ElementStatus {
+		if st.Owner != nil {
+			owned++
+		}
+	}
+	if owned != 6*15 {
+		t.Errorf("ElementStatus() owned = %d, want %d", owned, 6*15)
+	}
+}
+
+func TestHTTPSourceMissing(t *testing.T) {
+	src := fpltest.NewSource(t, "testdata")
+	ctx := context.Background()
+
+	if _, err := src.Live(ctx, 14); !errors.Is(err, fpl.ErrNotFound) {
+		t.Errorf("Live(14) err = %v, want ErrNotFound", err)
+	}
+	if _, err := src.LeagueDetails(ctx, 1); !errors.Is(err, fpl.ErrNotFound) {
+		t.Errorf("LeagueDetails(1) err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,297 @@
+package fpl
+
+import "time"
+
+type Stats struct {
+	Minutes                  int     `json:"minutes"`
+	GoalsScored              int     `json:"goals_scored"`
+	Assists                  int     `json:"assists"`
+	CleanSheets              int     `json:"clean_sheets"`
+	GoalsConceded            int     `json:"goals_conceded"`
+	OwnGoals                 int     `json:"own_goals"`
+	PenaltiesSaved           int     `json:"penalties_saved"`
+	PenaltiesMissed          int     `json:"penalties_missed"`
+	YellowCards              int     `json:"yellow_cards"`
+	RedCards                 int     `json:"red_cards"`
+	Saves                    int     `json:"saves"`
+	Bonus                    int     `json:"bonus"`
+	Bps                      int     `json:"bps"`
+	Influence                float64 `json:"influence"`
+	Creativity               float64 `json:"creativity"`
+	Threat                   float64 `json:"threat"`
+	IctIndex                 float64 `json:"ict_index"`
+	Starts                   int     `json:"starts"`
+	ExpectedGoals            float64 `json:"expected_goals"`
+	ExpectedAssists          float64 `json:"expected_assists"`
+	ExpectedGoalInvolvements float64 `json:"expected_goal_involvements"`
+	ExpectedGoalsConceded    float64 `json:"expected_goals_conceded"`
+	TotalPoints              int     `json:"total_points"`
+	InDreamteam              bool    `json:"in_dreamteam"`
+}
+type Element struct {
+	Explain string `json:"-"`
+	Stats   Stats  `json:"stats"`
+}
+type Live struct {
+	El map[uint16]Element `json:"elements"`
+}
+type Game struct {
+	CurrentEvent          uint8  `json:"current_event"`
+	CurrentEventFinished  bool   `json:"current_event_finished"`
+	NextEvent             uint8  `json:"next_event"`
+	ProcessingStatus      string `json:"processing_status"`
+	TradesTimeForApproval bool   `json:"trades_time_for_approval"`
+	WaiversProcessed      bool   `json:"waivers_processed"`
+}
+type League struct {
+	AdminEntry         int       `json:"admin_entry"`
+	Closed             bool      `json:"closed"`
+	DraftDt            time.Time `json:"draft_dt"`
+	DraftPickTimeLimit int       `json:"draft_pick_time_limit"`
+	DraftStatus        string    `json:"draft_status"`
+	DraftTzShow        string    `json:"draft_tz_show"`
+	ID                 int       `json:"id"`
+	KoRounds           int       `json:"ko_rounds"`
+	MakeCodePublic     bool      `json:"make_code_public"`
+	MaxEntries         int       `json:"max_entries"`
+	MinEntries         int       `json:"min_entries"`
+	Name               string    `json:"name"`
+	Scoring            string    `json:"scoring"`
+	StartEvent         int       `json:"start_event"`
+	StopEvent          int       `json:"stop_event"`
+	Trades             string    `json:"trades"`
+	TransactionMode    string    `json:"transaction_mode"`
+	Variety            string    `json:"variety"`
+}
+type LeagueEntries []struct {
+	EntryID         int       `json:"entry_id"`
+	EntryName       string    `json:"entry_name"`
+	ID              int       `json:"id"`
+	JoinedTime      time.Time `json:"joined_time"`
+	PlayerFirstName string    `json:"player_first_name"`
+	PlayerLastName  string    `json:"player_last_name"`
+	ShortName       string    `json:"short_name"`
+	WaiverPick      int       `json:"waiver_pick"`
+}
+type Matches []struct {
+	Event              int         `json:"event"`
+	Finished           bool        `json:"finished"`
+	LeagueEntry1       int         `json:"league_entry_1"`
+	LeagueEntry1Points int         `json:"league_entry_1_points"`
+	LeagueEntry2       int         `json:"league_entry_2"`
+	LeagueEntry2Points int         `json:"league_entry_2_points"`
+	Started            bool        `json:"started"`
+	WinningLeagueEntry interface{} `json:"winning_league_entry"`
+	WinningMethod      interface{} `json:"winning_method"`
+}
+type Standings []struct {
+	// LastRank, Rank and RankSort are null before the first gameweek has
+	// been processed and decode to 0.
+	LastRank      int `json:"last_rank"`
+	LeagueEntry   int `json:"league_entry"`
+	MatchesDrawn  int `json:"matches_drawn"`
+	MatchesLost   int `json:"matches_lost"`
+	MatchesPlayed int `json:"matches_played"`
+	MatchesWon    int `json:"matches_won"`
+	PointsAgainst int `json:"points_against"`
+	PointsFor     int `json:"points_for"`
+	Rank          int `json:"rank"`
+	RankSort      int `json:"rank_sort"`
+	Total         int `json:"total"`
+}
+type Draft struct {
+	League        League        `json:"league"`
+	LeagueEntries LeagueEntries `json:"league_entries"`
+	Matches       Matches       `json:"matches"`
+	Standings     Standings     `json:"standings"`
+}
+type Club struct {
+	Squad        Squad         `json:"picks"`
+	EntryHistory struct{}      `json:"-"`
+	Subs         []interface{} `json:"-"`
+}
+type Squad []struct {
+	Element       int  `json:"element"`
+	Position      int  `json:"position"`
+	IsCaptain     bool `json:"is_captain"`
+	IsViceCaptain bool `json:"is_vice_captain"`
+	Multiplier    int  `json:"multiplier"`
+}
+type Event struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	DeadlineTime time.Time `json:"deadline_time"`
+	Finished     bool      `json:"finished"`
+	TradesTime   time.Time `json:"trades_time"`
+	WaiversTime  time.Time `json:"waivers_time"`
+}
+type Events struct {
+	Current int     `json:"current"`
+	Next    int     `json:"next"`
+	Data    []Event `json:"data"`
+}
+type Team struct {
+	Code      int    `json:"code"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	PulseID   int    `json:"pulse_id"`
+	ShortName string `json:"short_name"`
+}
+type ElementType struct {
+	ID                int    `json:"id"`
+	ElementCount      int    `json:"element_count"`
+	SingularName      string `json:"singular_name"`
+	SingularNameShort string `json:"singular_name_short"`
+	PluralName        string `json:"plural_name"`
+	PluralNameShort   string `json:"plural_name_short"`
+}
+type ScoringSettings struct {
+	LongPlayLimit    int `json:"long_play_limit"`
+	ShortPlay        int `json:"short_play"`
+	LongPlay         int `json:"long_play"`
+	ConcedeLimit     int `json:"concede_limit"`
+	GoalsConcededGKP int `json:"goals_conceded_GKP"`
+	GoalsConcededDEF int `json:"goals_conceded_DEF"`
+	GoalsConcededMID int `json:"goals_conceded_MID"`
+	GoalsConcededFWD int `json:"goals_conceded_FWD"`
+	SavesLimit       int `json:"saves_limit"`
+	Saves            int `json:"saves"`
+	GoalsScoredGKP   int `json:"goals_scored_GKP"`
+	GoalsScoredDEF   int `json:"goals_scored_DEF"`
+	GoalsScoredMID   int `json:"goals_scored_MID"`
+	GoalsScoredFWD   int `json:"goals_scored_FWD"`
+	Assists          int `json:"assists"`
+	CleanSheetsGKP   int `json:"clean_sheets_GKP"`
+	CleanSheetsDEF   int `json:"clean_sheets_DEF"`
+	CleanSheetsMID   int `json:"clean_sheets_MID"`
+	CleanSheetsFWD   int `json:"clean_sheets_FWD"`
+	PenaltiesSaved   int `json:"penalties_saved"`
+	PenaltiesMissed  int `json:"penalties_missed"`
+	YellowCards      int `json:"yellow_cards"`
+	RedCards         int `json:"red_cards"`
+	OwnGoals         int `json:"own_goals"`
+	Bonus            int `json:"bonus"`
+}
+type SquadSettings struct {
+	Size       int `json:"size"`
+	Play       int `json:"play"`
+	MinPlayGKP int `json:"min_play_GKP"`
+	MaxPlayGKP int `json:"max_play_GKP"`
+	MinPlayDEF int `json:"min_play_DEF"`
+	MaxPlayDEF int `json:"max_play_DEF"`
+	MinPlayMID int `json:"min_play_MID"`
+	MaxPlayMID int `json:"max_play_MID"`
+	MinPlayFWD int `json:"min_play_FWD"`
+	MaxPlayFWD int `json:"max_play_FWD"`
+}
+type LeagueSettings struct {
+	H2HWin  int `json:"h2h_win"`
+	H2HDraw int `json:"h2h_draw"`
+	H2HLose int `json:"h2h_lose"`
+}
+
+// TransactionSettings holds the trade and waiver rules. An accepted
+// trade can be vetoed for TradeVetoHours by TradeVetoMinimum percent of
+// the league's managers.
+type TransactionSettings struct {
+	NewElementLockedHours      int `json:"new_element_locked_hours"`
+	TradeVetoMinimum           int `json:"trade_veto_minimum"`
+	TradeVetoHours             int `json:"trade_veto_hours"`
+	WaiversBeforeStartMinHours int `json:"waivers_before_start_min_hours"`
+	WaiversBeforeDeadlineHours int `json:"waivers_before_deadline_hours"`
+}
+type Settings struct {
+	League       LeagueSettings      `json:"league"`
+	Scoring      ScoringSettings     `json:"scoring"`
+	Squad        SquadSettings       `json:"squad"`
+	Transactions TransactionSettings `json:"transactions"`
+}
+type Bootstrap struct {
+	Players      []Player      `json:"elements"`
+	ElementTypes []ElementType `json:"element_types"`
+	Events       Events        `json:"events"`
+	Settings     Settings      `json:"settings"`
+	Teams        []Team        `json:"teams"`
+}
+type Stat struct {
+	S string  `json:"s"`
+	H []ElVal `json:"h"`
+	A []ElVal `json:"a"`
+}
+type ElVal struct {
+	Element int `json:"element"`
+	Value   int `json:"value"`
+}
+type Fixture struct {
+	ID                   int       `json:"id"`
+	Started              bool      `json:"started"`
+	Stats                []Stat    `json:"stats"`
+	Code                 int       `json:"code"`
+	Finished             bool      `json:"finished"`
+	FinishedProvisional  bool      `json:"finished_provisional"`
+	KickoffTime          time.Time `json:"kickoff_time"`
+	Minutes              int       `json:"minutes"`
+	ProvisionalStartTime bool      `json:"provisional_start_time"`
+	TeamAScore           int       `json:"team_a_score"`
+	TeamHScore           int       `json:"team_h_score"`
+	PulseID              int       `json:"pulse_id"`
+	Event                int       `json:"event"`
+	TeamA                int       `json:"team_a"`
+	TeamH                int       `json:"team_h"`
+}
+type Fixtures []Fixture
+
+// Transaction is a waiver claim or free-agent swap. Entry is the entry ID,
+// not the league entry. Kind is "w" for waivers and "f" for free agents;
+// Result is "a" when accepted, "di" when denied as invalid and "do" when
+// outbid by a manager higher in the waiver order.
+type Transaction struct {
+	Added      time.Time `json:"added"`
+	ElementIn  int       `json:"element_in"`
+	ElementOut int       `json:"element_out"`
+	Entry      int       `json:"entry"`
+	Event      int       `json:"event"`
+	ID         int       `json:"id"`
+	Index      int       `json:"index"`
+	Kind       string    `json:"kind"`
+	Priority   int       `json:"priority"`
+	Result     string    `json:"result"`
+}
+type Transactions struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Trade is a swap offered by one entry to another; both are entry IDs.
+// The offering entry receives every ElementIn and gives up every
+// ElementOut. State is "o" offered, "a" accepted and awaiting the veto
+// window, "p" processed, "v" vetoed, "r" rejected, "w" withdrawn, "e"
+// expired or "i" invalid.
+type Trade struct {
+	ID            int        `json:"id"`
+	Event         int        `json:"event"`
+	OfferedEntry  int        `json:"offered_entry"`
+	ReceivedEntry int        `json:"received_entry"`
+	OfferTime     time.Time  `json:"offer_time"`
+	ResponseTime  *time.Time `json:"response_time"`
+	State         string     `json:"state"`
+	TradeItemSet  []struct {
+		ElementIn  int `json:"element_in"`
+		ElementOut int `json:"element_out"`
+	} `json:"tradeitem_set"`
+}
+type Trades struct {
+	Trades []Trade `json:"trades"`
+}
+
+// ElementStatus is a player's standing in one league. Owner is the entry
+// ID holding him, or nil for a free agent. Status is "o" when owned, "w"
+// while he can only be claimed on waivers and "a" when he is available.
+type ElementStatus struct {
+	Element         int    `json:"element"`
+	InAcceptedTrade bool   `json:"in_accepted_trade"`
+	Owner           *int   `json:"owner"`
+	Status          string `json:"status"`
+}
+type ElementStatuses struct {
+	ElementStatus []ElementStatus `json:"element_status"`
+}
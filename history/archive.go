@@ -0,0 +1,385 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// Archive keeps a season history on disk in front of another
+// fpl.Source. Finished gameweeks are written once and then served from
+// disk, and every endpoint falls back to the archive when upstream fails,
+// so past gameweeks keep working if the API changes or goes away.
+//
+// Each season gets its own directory, named like "2023-24", holding files
+// named the way fpl.FileSource reads them, so a season directory can also be
+// served directly with -data-dir:
+//
+//	data-game.json, data-bootstrap-static.json       latest state
+//	data-draft-league-{id}.json                      latest matches and standings
+//	data-transactions-{id}.json                      every transaction so far
+//	data-trades-{id}.json                            every trade so far
+//	data-element-status-{id}.json                    latest player ownership
+//	data-live-{n}.json, data-fixtures-{n}.json       final gameweek n
+//	data-entry-{entry}-{n}.json                      final picks for gameweek n
+type Archive struct {
+	src fpl.Source
+	dir string
+
+	mu     sync.Mutex
+	season string
+	game   fpl.Game
+	known  bool
+}
+
+// NewArchive keeps the archive of src in dir.
+func NewArchive(src fpl.Source, dir string) *Archive {
+	return &Archive{src: src, dir: dir}
+}
+
+// seasonName names a season after the calendar years it spans.
+func seasonName(bootstrap fpl.Bootstrap) string {
+	if len(bootstrap.Events.Data) == 0 {
+		return ""
+	}
+	year := bootstrap.Events.Data[0].DeadlineTime.Year()
+	return fmt.Sprintf("%d-%02d", year, (year+1)%100)
+}
+
+// seasonDir is the directory of the season being played, or the latest
+// archived one until a bootstrap has been seen.
+func (a *Archive) seasonDir() string {
+	a.mu.Lock()
+	season := a.season
+	a.mu.Unlock()
+	if season != "" {
+		return filepath.Join(a.dir, season)
+	}
+
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return ""
+	}
+	var seasons []string
+	for _, e := range entries {
+		if e.IsDir() {
+			seasons = append(seasons, e.Name())
+		}
+	}
+	if len(seasons) == 0 {
+		return ""
+	}
+	sort.Strings(seasons)
+	return filepath.Join(a.dir, seasons[len(seasons)-1])
+}
+
+// learnSeason fetches the bootstrap if the season isn't known yet, so
+// responses that arrive before it can still be archived.
+func (a *Archive) learnSeason(ctx context.Context) {
+	a.mu.Lock()
+	season := a.season
+	a.mu.Unlock()
+	if season == "" {
+		a.Bootstrap(ctx)
+	}
+}
+
+// final reports whether gw has finished, so its data will not change.
+func (a *Archive) final(gw uint8) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.known {
+		return false
+	}
+	return gw < a.game.CurrentEvent || (gw == a.game.CurrentEvent && a.game.CurrentEventFinished)
+}
+
+func (a *Archive) read(v interface{}, name string) error {
+	dir := a.seasonDir()
+	if dir == "" {
+		return fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return fpl.NewFileSource(dir).ReadJSON(v, name)
+}
+
+// write stores v under name, replacing any earlier copy atomically.
+// Failures are logged: the archive must never break the live dashboard.
+func (a *Archive) write(name string, v interface{}) {
+	a.mu.Lock()
+	season := a.season
+	a.mu.Unlock()
+	if season == "" {
+		return
+	}
+
+	dir := filepath.Join(a.dir, season)
+	if err := writeJSONFile(dir, name, v); err != nil {
+		log.Printf("archive %s: %v", name, err)
+	}
+}
+
+func writeJSONFile(dir, name string, v interface{}) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(v); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// gameweek serves a finished gameweek's name from disk, otherwise calls
+// get, archiving the result once the gameweek is final. When get fails
+// any archived copy is used instead.
+func (a *Archive) gameweek(v interface{}, name string, gw uint8, get func() error) error {
+	if a.final(gw) && a.read(v, name) == nil {
+		return nil
+	}
+	if err := get(); err != nil {
+		if a.read(v, name) == nil {
+			log.Printf("archive: serving %s after: %v", name, err)
+			return nil
+		}
+		return err
+	}
+	if a.final(gw) {
+		a.write(name, v)
+	}
+	return nil
+}
+
+// latest calls get for the newest copy of name and archives it. When
+// get fails any archived copy is used instead. merge, if set, runs before
+// writing, once the season is known, to fold in the archived copy.
+func (a *Archive) latest(ctx context.Context, v interface{}, name string, get func() error, merge func()) error {
+	if err := get(); err != nil {
+		if a.read(v, name) == nil {
+			log.Printf("archive: serving %s after: %v", name, err)
+			return nil
+		}
+		return err
+	}
+
+	a.learnSeason(ctx)
+	a.mu.Lock()
+	known := a.season != ""
+	a.mu.Unlock()
+	if merge != nil && known {
+		merge()
+	}
+	a.write(name, v)
+	return nil
+}
+
+func (a *Archive) Game(ctx context.Context) (fpl.Game, error) {
+	game, err := a.src.Game(ctx)
+	if err != nil {
+		if dir := a.seasonDir(); dir != "" {
+			if archived, aerr := fpl.NewFileSource(dir).Game(ctx); aerr == nil {
+				log.Printf("archive: serving game after: %v", err)
+				return archived, nil
+			}
+		}
+		return game, err
+	}
+
+	a.mu.Lock()
+	a.game, a.known = game, true
+	a.mu.Unlock()
+	a.learnSeason(ctx)
+	a.write("data-game.json", game)
+	return game, nil
+}
+
+// LeagueDetails archives the latest matches and standings. Past
+// gameweeks' standings are rebuilt from the matches, so no per-gameweek
+// copy is kept.
+func (a *Archive) LeagueDetails(ctx context.Context, leagueID int) (fpl.Draft, error) {
+	var draft fpl.Draft
+	err := a.latest(ctx, &draft, "data-draft-league-"+strconv.Itoa(leagueID)+".json", func() (err error) {
+		draft, err = a.src.LeagueDetails(ctx, leagueID)
+		return err
+	}, nil)
+	return draft, err
+}
+
+func (a *Archive) Live(ctx context.Context, gw uint8) (fpl.Live, error) {
+	var live fpl.Live
+	err := a.gameweek(&live, "data-live-"+strconv.Itoa(int(gw))+".json", gw, func() (err error) {
+		live, err = a.src.Live(ctx, gw)
+		return err
+	})
+	return live, err
+}
+
+func (a *Archive) EntryPicks(ctx context.Context, entry uint32, gw uint8) (fpl.Club, error) {
+	var club fpl.Club
+	name := "data-entry-" + strconv.Itoa(int(entry)) + "-" + strconv.Itoa(int(gw)) + ".json"
+	err := a.gameweek(&club, name, gw, func() (err error) {
+		club, err = a.src.EntryPicks(ctx, entry, gw)
+		return err
+	})
+	return club, err
+}
+
+func (a *Archive) Bootstrap(ctx context.Context) (fpl.Bootstrap, error) {
+	bootstrap, err := a.src.Bootstrap(ctx)
+	if err != nil {
+		var archived fpl.Bootstrap
+		if a.read(&archived, "data-bootstrap-static.json") == nil {
+			log.Printf("archive: serving bootstrap after: %v", err)
+			return archived, nil
+		}
+		return bootstrap, err
+	}
+
+	a.mu.Lock()
+	if season := seasonName(bootstrap); season != "" {
+		a.season = season
+	}
+	a.mu.Unlock()
+	a.write("data-bootstrap-static.json", bootstrap)
+	return bootstrap, nil
+}
+
+func (a *Archive) Fixtures(ctx context.Context, gw uint8) (fpl.Fixtures, error) {
+	var fixtures fpl.Fixtures
+	err := a.gameweek(&fixtures, "data-fixtures-"+strconv.Itoa(int(gw))+".json", gw, func() (err error) {
+		fixtures, err = a.src.Fixtures(ctx, gw)
+		return err
+	})
+	return fixtures, err
+}
+
+// Transactions archives the league's transactions so the season's
+// history survives the API. Archived transactions the API no longer
+// returns are kept.
+func (a *Archive) Transactions(ctx context.Context, leagueID int) (fpl.Transactions, error) {
+	var txs fpl.Transactions
+	name := "data-transactions-" + strconv.Itoa(leagueID) + ".json"
+	err := a.latest(ctx, &txs, name, func() (err error) {
+		txs, err = a.src.Transactions(ctx, leagueID)
+		return err
+	}, func() {
+		var archived fpl.Transactions
+		if a.read(&archived, name) != nil {
+			return
+		}
+		byID := map[int]fpl.Transaction{}
+		for _, tx := range archived.Transactions {
+			byID[tx.ID] = tx
+		}
+		for _, tx := range txs.Transactions {
+			byID[tx.ID] = tx
+		}
+		txs.Transactions = txs.Transactions[:0:0]
+		for _, tx := range byID {
+			txs.Transactions = append(txs.Transactions, tx)
+		}
+		sort.Slice(txs.Transactions, func(i, j int) bool {
+			return txs.Transactions[i].ID < txs.Transactions[j].ID
+		})
+	})
+	return txs, err
+}
+
+// Trades archives the league's trades alongside its transactions, keeping
+// archived trades the API no longer returns.
+func (a *Archive) Trades(ctx context.Context, leagueID int) (fpl.Trades, error) {
+	var trades fpl.Trades
+	name := "data-trades-" + strconv.Itoa(leagueID) + ".json"
+	err := a.latest(ctx, &trades, name, func() (err error) {
+		trades, err = a.src.Trades(ctx, leagueID)
+		return err
+	}, func() {
+		var archived fpl.Trades
+		if a.read(&archived, name) != nil {
+			return
+		}
+		byID := map[int]fpl.Trade{}
+		for _, t := range archived.Trades {
+			byID[t.ID] = t
+		}
+		for _, t := range trades.Trades {
+			byID[t.ID] = t
+		}
+		trades.Trades = trades.Trades[:0:0]
+		for _, t := range byID {
+			trades.Trades = append(trades.Trades, t)
+		}
+		sort.Slice(trades.Trades, func(i, j int) bool {
+			return trades.Trades[i].ID < trades.Trades[j].ID
+		})
+	})
+	return trades, err
+}
+
+// ElementStatus archives who owns each player in the league. Only the
+// latest ownership is kept; it is not part of the season's history.
+func (a *Archive) ElementStatus(ctx context.Context, leagueID int) (fpl.ElementStatuses, error) {
+	var statuses fpl.ElementStatuses
+	err := a.latest(ctx, &statuses, "data-element-status-"+strconv.Itoa(leagueID)+".json", func() (err error) {
+		statuses, err = a.src.ElementStatus(ctx, leagueID)
+		return err
+	}, nil)
+	return statuses, err
+}
+
+// Backfill loads every finished gameweek of a league's season through
+// src so that an Archive behind it stores them: live data, fixtures and
+// the picks of every entry, plus the league's transactions and trades.
+// Gameweeks already on disk cost no upstream requests.
+func Backfill(ctx context.Context, src fpl.Source, leagueID int) {
+	game, err := src.Game(ctx)
+	if err != nil {
+		log.Printf("league %d: archive backfill: %v", leagueID, err)
+		return
+	}
+	draft, err := src.LeagueDetails(ctx, leagueID)
+	if err != nil {
+		log.Printf("league %d: archive backfill: %v", leagueID, err)
+		return
+	}
+	if _, err := src.Transactions(ctx, leagueID); err != nil {
+		log.Printf("league %d: archive backfill transactions: %v", leagueID, err)
+	}
+	if _, err := src.Trades(ctx, leagueID); err != nil {
+		log.Printf("league %d: archive backfill trades: %v", leagueID, err)
+	}
+	last := int(game.CurrentEvent)
+	if !game.CurrentEventFinished {
+		last--
+	}
+	for gw := draft.League.StartEvent; gw <= last; gw++ {
+		event := uint8(gw)
+		if _, err := src.Live(ctx, event); err != nil {
+			log.Printf("league %d: archive backfill gw %d: %v", leagueID, gw, err)
+		}
+		if _, err := src.Fixtures(ctx, event); err != nil {
+			log.Printf("league %d: archive backfill gw %d: %v", leagueID, gw, err)
+		}
+		for _, user := range draft.LeagueEntries {
+			if _, err := src.EntryPicks(ctx, uint32(user.EntryID), event); err != nil {
+				log.Printf("league %d: archive backfill gw %d entry %d: %v", leagueID, gw, user.EntryID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package history keeps what the draft API forgets: an on-disk archive
+// of finished gameweeks, and timestamped recordings of live gameweeks that
+// can be replayed.
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// Snapshot kinds kept by a recording.
+const (
+	recordLive     = "live"
+	recordFixtures = "fixtures"
+)
+
+// Recording is a directory of timestamped live and fixtures snapshots,
+// one sub-directory per gameweek:
+//
+//	gw{n}/{unix nanoseconds}-live.json
+//	gw{n}/{unix nanoseconds}-fixtures.json
+type Recording struct {
+	dir string
+}
+
+// NewRecording opens the recording kept in dir.
+func NewRecording(dir string) Recording {
+	return Recording{dir: dir}
+}
+
+func (r Recording) gwDir(gw uint8) string {
+	return filepath.Join(r.dir, "gw"+strconv.Itoa(int(gw)))
+}
+
+// snapshotTimes lists when snapshots of kind were taken for gw, oldest
+// first.
+func (r Recording) snapshotTimes(gw uint8, kind string) ([]time.Time, error) {
+	entries, err := os.ReadDir(r.gwDir(gw))
+	if err != nil {
+		return nil, err
+	}
+	var times []time.Time
+	for _, e := range entries {
+		stamp := strings.TrimSuffix(e.Name(), "-"+kind+".json")
+		if stamp == e.Name() {
+			continue
+		}
+		ns, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		times = append(times, time.Unix(0, ns))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times, nil
+}
+
+// Times lists every moment gw changed, from either kind of snapshot.
+func (r Recording) Times(gw uint8) ([]time.Time, error) {
+	live, err := r.snapshotTimes(gw, recordLive)
+	if err != nil {
+		return nil, err
+	}
+	fixtures, err := r.snapshotTimes(gw, recordFixtures)
+	if err != nil {
+		return nil, err
+	}
+	all := append(live, fixtures...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Before(all[j]) })
+	var times []time.Time
+	for _, t := range all {
+		if len(times) == 0 || !t.Equal(times[len(times)-1]) {
+			times = append(times, t)
+		}
+	}
+	return times, nil
+}
+
+// load decodes the latest snapshot of kind taken at or before at, or the
+// first one if at precedes the recording.
+func (r Recording) load(gw uint8, kind string, at time.Time, v interface{}) error {
+	times, err := r.snapshotTimes(gw, kind)
+	if err != nil {
+		return err
+	}
+	if len(times) == 0 {
+		return fmt.Errorf("gw %d: no %s snapshots: %w", gw, kind, os.ErrNotExist)
+	}
+	pick := times[0]
+	for _, t := range times {
+		if t.After(at) {
+			break
+		}
+		pick = t
+	}
+	name := strconv.FormatInt(pick.UnixNano(), 10) + "-" + kind + ".json"
+	return fpl.NewFileSource(r.gwDir(gw)).ReadJSON(v, name)
+}
+
+// latest returns the raw body of the newest snapshot of kind, or nil.
+func (r Recording) latest(gw uint8, kind string) []byte {
+	times, err := r.snapshotTimes(gw, kind)
+	if err != nil || len(times) == 0 {
+		return nil
+	}
+	name := strconv.FormatInt(times[len(times)-1].UnixNano(), 10) + "-" + kind + ".json"
+	body, err := os.ReadFile(filepath.Join(r.gwDir(gw), name))
+	if err != nil {
+		return nil
+	}
+	return bytes.TrimSpace(body)
+}
+
+// Recorder saves a snapshot of the current gameweek's live data and
+// fixtures whenever they change, for replaying later. Other gameweeks and
+// endpoints pass straight through.
+type Recorder struct {
+	fpl.Source
+	rec Recording
+
+	mu      sync.Mutex
+	current uint8
+	last    map[string][]byte
+}
+
+func NewRecorder(src fpl.Source, dir string) *Recorder {
+	return &Recorder{
+		Source: src,
+		rec:    Recording{dir: dir},
+		last:   map[string][]byte{},
+	}
+}
+
+func (s *Recorder) Game(ctx context.Context) (fpl.Game, error) {
+	game, err := s.Source.Game(ctx)
+	if err == nil {
+		s.mu.Lock()
+		s.current = game.CurrentEvent
+		s.mu.Unlock()
+	}
+	return game, err
+}
+
+// save writes v as a new snapshot unless it matches the previous one.
+func (s *Recorder) save(gw uint8, kind string, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("record gw %d %s: %v", gw, kind, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gw != s.current {
+		return
+	}
+	key := strconv.Itoa(int(gw)) + "/" + kind
+	if _, ok := s.last[key]; !ok {
+		s.last[key] = s.rec.latest(gw, kind)
+	}
+	if bytes.Equal(s.last[key], body) {
+		return
+	}
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + kind + ".json"
+	if err := writeJSONFile(s.rec.gwDir(gw), name, json.RawMessage(body)); err != nil {
+		log.Printf("record gw %d %s: %v", gw, kind, err)
+		return
+	}
+	s.last[key] = body
+}
+
+func (s *Recorder) Live(ctx context.Context, gw uint8) (fpl.Live, error) {
+	live, err := s.Source.Live(ctx, gw)
+	if err == nil {
+		s.save(gw, recordLive, live)
+	}
+	return live, err
+}
+
+func (s *Recorder) Fixtures(ctx context.Context, gw uint8) (fpl.Fixtures, error) {
+	fixtures, err := s.Source.Fixtures(ctx, gw)
+	if err == nil {
+		s.save(gw, recordFixtures, fixtures)
+	}
+	return fixtures, err
+}
+
+// Replay shows gameweek gw as it stood at a recorded moment: live
+// data and fixtures come from the recording, the game is rewound so gw
+// is the current gameweek, and its head-to-heads are reopened so totals
+// are computed from the snapshot rather than the final scores.
+type Replay struct {
+	fpl.Source
+	rec Recording
+	gw  uint8
+	at  time.Time
+}
+
+// NewReplay serves gw from rec as it stood at the moment at, and
+// everything else from src.
+func NewReplay(src fpl.Source, rec Recording, gw uint8, at time.Time) *Replay {
+	return &Replay{Source: src, rec: rec, gw: gw, at: at}
+}
+
+func (s *Replay) Live(ctx context.Context, gw uint8) (fpl.Live, error) {
+	var live fpl.Live
+	if gw != s.gw {
+		return s.Source.Live(ctx, gw)
+	}
+	err := s.rec.load(gw, recordLive, s.at, &live)
+	return live, err
+}
+
+func (s *Replay) Fixtures(ctx context.Context, gw uint8) (fpl.Fixtures, error) {
+	var fixtures fpl.Fixtures
+	if gw != s.gw {
+		return s.Source.Fixtures(ctx, gw)
+	}
+	err := s.rec.load(gw, recordFixtures, s.at, &fixtures)
+	return fixtures, err
+}
+
+// finished reports whether every recorded fixture had finished by then.
+func (s *Replay) finished(ctx context.Context) bool {
+	fixtures, err := s.Fixtures(ctx, s.gw)
+	if err != nil || len(fixtures) == 0 {
+		return false
+	}
+	for _, f := range fixtures {
+		if !f.Finished {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Replay) Game(ctx context.Context) (fpl.Game, error) {
+	game, err := s.Source.Game(ctx)
+	if err != nil {
+		return game, err
+	}
+	game.CurrentEvent = s.gw
+	game.NextEvent = s.gw + 1
+	game.CurrentEventFinished = s.finished(ctx)
+	return game, nil
+}
+
+func (s *Replay) LeagueDetails(ctx context.Context, leagueID int) (fpl.Draft, error) {
+	draft, err := s.Source.LeagueDetails(ctx, leagueID)
+	if err != nil || s.finished(ctx) {
+		return draft, err
+	}
+	matches := append(fpl.Matches(nil), draft.Matches...)
+	for i := range matches {
+		if matches[i].Event == int(s.gw) {
+			matches[i].Finished = false
+		}
+	}
+	draft.Matches = matches
+	return draft, nil
+}
@@ -0,0 +1,76 @@
+// Package render turns a dashboard into HTML with the embedded page
+// templates.
+package render
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templateFuncs = template.FuncMap{
+	"add":   func(a, b int) int { return a + b },
+	"neg":   func(a int) int { return -a },
+	"until": until,
+}
+
+// until formats the time left before t to the minute, e.g. "5h 12m", or
+// "processing" once it has passed. Pages work it out when rendered, since
+// the dashboard they show can be half an hour old.
+func until(t time.Time) string {
+	d := time.Until(t)
+	if d <= 0 {
+		return "processing"
+	}
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h == 0 {
+		return strconv.Itoa(m) + "m"
+	}
+	return strconv.Itoa(h) + "h " + strconv.Itoa(m) + "m"
+}
+
+// Renderer executes the page templates. In dev mode the templates are
+// re-read from disk on every render, so edits show up on reload.
+type Renderer struct {
+	dir  string
+	tmpl *template.Template
+}
+
+// New returns a Renderer using the embedded templates, or the ones in
+// devDir when it is set.
+func New(devDir string) (*Renderer, error) {
+	r := &Renderer{dir: devDir}
+	if devDir != "" {
+		return r, nil
+	}
+
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	r.tmpl = tmpl
+	return r, nil
+}
+
+func (r *Renderer) templates() (*template.Template, error) {
+	if r.dir == "" {
+		return r.tmpl, nil
+	}
+	return template.New("").Funcs(templateFuncs).ParseFS(os.DirFS(r.dir), "*.html")
+}
+
+// Render writes the named template for data.
+func (r *Renderer) Render(w io.Writer, name string, data interface{}) error {
+	tmpl, err := r.templates()
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
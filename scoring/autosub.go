@@ -0,0 +1,129 @@
+package scoring
+
+import (
+	"sort"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// Pick is what the auto-sub engine needs to know about one pick.
+type Pick struct {
+	Element     int
+	Position    int // 1-11 starting, 12-15 bench in priority order
+	ElementType int
+	Played      bool // has minutes this gameweek
+	Done        bool // every fixture of the player's club is over
+}
+
+// Substitution is an automatic substitution: Out is replaced by In.
+type Substitution struct {
+	Out int `json:"out"`
+	In  int `json:"in"`
+}
+
+// Starters is how many picks start: the league's play setting, or 11 when
+// the bootstrap leaves it out. Picks in later positions are on the bench.
+func Starters(r fpl.SquadSettings) int {
+	if r.Play == 0 {
+		return 11
+	}
+	return r.Play
+}
+
+// playLimits returns how many players of an element type may start.
+func playLimits(r fpl.SquadSettings, elementType int) (min, max int) {
+	switch elementType {
+	case ElementGKP:
+		return r.MinPlayGKP, r.MaxPlayGKP
+	case ElementDEF:
+		return r.MinPlayDEF, r.MaxPlayDEF
+	case ElementMID:
+		return r.MinPlayMID, r.MaxPlayMID
+	case ElementFWD:
+		return r.MinPlayFWD, r.MaxPlayFWD
+	}
+	return 0, 0
+}
+
+// validFormation checks the per-position limits for a starting XI given
+// as element type counts.
+func validFormation(r fpl.SquadSettings, counts map[int]int) bool {
+	for _, et := range []int{ElementGKP, ElementDEF, ElementMID, ElementFWD} {
+		min, max := playLimits(r, et)
+		if counts[et] < min || (max > 0 && counts[et] > max) {
+			return false
+		}
+	}
+	return true
+}
+
+// AutoSubs applies the draft auto-substitution rules. A starter who has
+// not played once all of his club's fixtures are over is replaced by the
+// first bench player, in bench order, who has played and keeps the
+// formation within the squad limits. Goalkeepers only swap with
+// goalkeepers. A bench player whose club has yet to play holds his
+// place: the sub stays pending rather than going to the next one. It
+// returns the elements that make up the effective XI and the
+// substitutions made.
+func AutoSubs(picks []Pick, rules fpl.SquadSettings) (map[int]bool, []Substitution) {
+	sorted := append([]Pick(nil), picks...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+
+	play := Starters(rules)
+
+	effective := map[int]bool{}
+	counts := map[int]int{}
+	var starters, bench []Pick
+	for _, p := range sorted {
+		if p.Position <= play {
+			starters = append(starters, p)
+			effective[p.Element] = true
+			counts[p.ElementType]++
+		} else {
+			bench = append(bench, p)
+		}
+	}
+
+	used := map[int]bool{}
+	var subs []Substitution
+	for _, out := range starters {
+		if out.Played || !out.Done {
+			continue
+		}
+		for _, in := range bench {
+			if used[in.Element] {
+				continue
+			}
+			if (out.ElementType == ElementGKP) != (in.ElementType == ElementGKP) {
+				continue
+			}
+
+			counts[out.ElementType]--
+			counts[in.ElementType]++
+			valid := validFormation(rules, counts)
+			if !valid || !in.Played {
+				counts[in.ElementType]--
+				counts[out.ElementType]++
+			}
+			if !valid {
+				continue
+			}
+			if !in.Played {
+				if !in.Done {
+					// Wait for him rather than skip ahead on the bench.
+					break
+				}
+				continue
+			}
+
+			used[in.Element] = true
+			delete(effective, out.Element)
+			effective[in.Element] = true
+			subs = append(subs, Substitution{Out: out.Element, In: in.Element})
+			break
+		}
+	}
+	return effective, subs
+}
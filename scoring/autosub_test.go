@@ -0,0 +1,99 @@
+package scoring
+
+import (
+	"reflect"
+	"testing"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+var squadRules = fpl.SquadSettings{
+	Size: 15, Play: 11,
+	MinPlayGKP: 1, MaxPlayGKP: 1,
+	MinPlayDEF: 3, MaxPlayDEF: 5,
+	MinPlayMID: 2, MaxPlayMID: 5,
+	MinPlayFWD: 1, MaxPlayFWD: 3,
+}
+
+// squad is a 4-4-2 with a goalkeeper, defender, midfielder and forward on
+// the bench, in that order. Element IDs equal positions; everyone has
+// played unless changed.
+func squad(change func(p []Pick)) []Pick {
+	types := []int{
+		ElementGKP, ElementDEF, ElementDEF, ElementDEF, ElementDEF,
+		ElementMID, ElementMID, ElementMID, ElementMID, ElementFWD, ElementFWD,
+		ElementGKP, ElementDEF, ElementMID, ElementFWD,
+	}
+	picks := make([]Pick, len(types))
+	for i, et := range types {
+		picks[i] = Pick{Element: i + 1, Position: i + 1, ElementType: et, Played: true, Done: true}
+	}
+	change(picks)
+	return picks
+}
+
+func TestAutoSubs(t *testing.T) {
+	tests := []struct {
+		name  string
+		picks []Pick
+		subs  []Substitution
+	}{
+		{
+			name:  "everyone played",
+			picks: squad(func(p []Pick) {}),
+		},
+		{
+			name:  "first outfield bench player comes on",
+			picks: squad(func(p []Pick) { p[5].Played = false }),
+			subs:  []Substitution{{Out: 6, In: 13}},
+		},
+		{
+			name: "bench player who did not play is skipped",
+			picks: squad(func(p []Pick) {
+				p[5].Played = false
+				p[12].Played = false
+			}),
+			subs: []Substitution{{Out: 6, In: 14}},
+		},
+		{
+			name: "bench player yet to play holds the sub",
+			picks: squad(func(p []Pick) {
+				p[5].Played = false
+				p[12].Played, p[12].Done = false, false
+			}),
+		},
+		{
+			name:  "starter yet to play is not subbed",
+			picks: squad(func(p []Pick) { p[5].Played, p[5].Done = false, false }),
+		},
+		{
+			name:  "goalkeeper swaps with goalkeeper",
+			picks: squad(func(p []Pick) { p[0].Played = false }),
+			subs:  []Substitution{{Out: 1, In: 12}},
+		},
+		{
+			name: "formation minimum skips to the forward",
+			picks: squad(func(p []Pick) {
+				p[9].Played = false
+				p[10].Played = false
+			}),
+			subs: []Substitution{{Out: 10, In: 13}, {Out: 11, In: 15}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective, subs := AutoSubs(tt.picks, squadRules)
+			if !reflect.DeepEqual(subs, tt.subs) {
+				t.Errorf("subs = %v, want %v", subs, tt.subs)
+			}
+			if len(effective) != 11 {
+				t.Errorf("effective XI has %d players", len(effective))
+			}
+			for _, s := range subs {
+				if effective[s.Out] || !effective[s.In] {
+					t.Errorf("effective XI does not reflect %v", s)
+				}
+			}
+		})
+	}
+}
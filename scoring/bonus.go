@@ -0,0 +1,108 @@
+package scoring
+
+import (
+	"sort"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// BonusState says where a fixture's bonus points come from.
+type BonusState string
+
+const (
+	BonusNone        BonusState = "none"        // not started, no bonus yet
+	BonusProvisional BonusState = "provisional" // derived from live BPS
+	BonusConfirmed   BonusState = "confirmed"   // awarded by the feed
+)
+
+// BonusStateOf reports whether a fixture's bonus is still provisional. The
+// feed only awards bonus once a fixture is fully Finished; until then,
+// including the finished_provisional window, it has to be derived.
+func BonusStateOf(f fpl.Fixture) BonusState {
+	switch {
+	case f.Finished:
+		return BonusConfirmed
+	case f.Started || f.FinishedProvisional:
+		return BonusProvisional
+	}
+	return BonusNone
+}
+
+// ProvisionalBonus awards 3, 2 and 1 bonus points to the top three BPS
+// scores. Tied players share the higher place and push the next player
+// down, e.g. two tied for first get 3 each and the next gets 1, two tied
+// for second get 2 each and the next gets nothing. Players without a
+// positive BPS get no bonus. The input is not modified.
+func ProvisionalBonus(bps []fpl.ElVal) map[uint16]int {
+	ranked := append([]fpl.ElVal(nil), bps...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Value > ranked[j].Value
+	})
+
+	bonus := map[uint16]int{}
+	rank := 0
+	for i, val := range ranked {
+		if i == 0 || val.Value != ranked[i-1].Value {
+			rank = i + 1
+		}
+		if rank > 3 || val.Value <= 0 {
+			break
+		}
+		bonus[uint16(val.Element)] = 4 - rank
+	}
+	return bonus
+}
+
+// FixtureBonus returns the bonus of every player in a fixture: the
+// feed's own "bonus" stat once confirmed, otherwise the provisional
+// bonus from the "bps" stat.
+func FixtureBonus(f fpl.Fixture) (map[uint16]int, BonusState) {
+	state := BonusStateOf(f)
+	if state == BonusNone {
+		return map[uint16]int{}, state
+	}
+
+	var bps []fpl.ElVal
+	for _, st := range f.Stats {
+		switch st.S {
+		case "bonus":
+			if state != BonusConfirmed {
+				continue
+			}
+			bonus := map[uint16]int{}
+			for _, ev := range append(append([]fpl.ElVal(nil), st.H...), st.A...) {
+				bonus[uint16(ev.Element)] = ev.Value
+			}
+			return bonus, state
+		case "bps":
+			bps = append(append([]fpl.ElVal(nil), st.H...), st.A...)
+		}
+	}
+	return ProvisionalBonus(bps), state
+}
+
+// BonusTally is one player's bonus across a gameweek's fixtures, split
+// by whether the feed has awarded it yet.
+type BonusTally struct {
+	Confirmed   int // from the "bonus" stat of finished fixtures
+	Provisional int // from BPS in fixtures still being played
+}
+
+// ReconcileBonus returns a player's points and bonus with every
+// fixture's bonus counted exactly once. fpl.Stats.TotalPoints already
+// includes fpl.Stats.Bonus, the bonus the live feed has confirmed. When the
+// fixtures endpoint confirms bonus before the live feed catches up, the
+// larger confirmed figure wins. Provisional bonus is only added while
+// the feed holds no bonus beyond the finished fixtures; once it does,
+// the feed's figure replaces it.
+func ReconcileBonus(s fpl.Stats, t BonusTally) (points, bonus int) {
+	confirmed, provisional := s.Bonus, t.Provisional
+	if t.Confirmed > confirmed {
+		confirmed = t.Confirmed
+	}
+	if s.Bonus > t.Confirmed {
+		provisional = 0
+	}
+	bonus = confirmed + provisional
+	return s.TotalPoints - s.Bonus + bonus, bonus
+}
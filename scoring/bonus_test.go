@@ -0,0 +1,230 @@
+package scoring
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// readJSON decodes one of the captures at the repository root.
+func readJSON(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := os.ReadFile("../" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+}
+
+// elVals builds stat values from element, value pairs.
+func elVals(pairs ...int) []fpl.ElVal {
+	var vals []fpl.ElVal
+	for i := 0; i+1 < len(pairs); i += 2 {
+		vals = append(vals, fpl.ElVal{Element: pairs[i], Value: pairs[i+1]})
+	}
+	return vals
+}
+
+func TestProvisionalBonus(t *testing.T) {
+	tests := []struct {
+		name string
+		bps  []fpl.ElVal
+		want map[uint16]int
+	}{
+		{
+			name: "top three",
+			bps:  elVals(1, 20, 2, 35, 3, 10, 4, 28),
+			want: map[uint16]int{2: 3, 4: 2, 1: 1},
+		},
+		{
+			name: "two tied for first",
+			bps:  elVals(1, 30, 2, 30, 3, 25, 4, 20),
+			want: map[uint16]int{1: 3, 2: 3, 3: 1},
+		},
+		{
+			name: "two tied for second",
+			bps:  elVals(1, 30, 2, 25, 3, 25, 4, 20),
+			want: map[uint16]int{1: 3, 2: 2, 3: 2},
+		},
+		{
+			name: "three tied for first",
+			bps:  elVals(1, 30, 2, 30, 3, 30, 4, 20),
+			want: map[uint16]int{1: 3, 2: 3, 3: 3},
+		},
+		{
+			name: "zero and negative bps",
+			bps:  elVals(1, 12, 2, 0, 3, -3),
+			want: map[uint16]int{1: 3},
+		},
+		{
+			name: "empty",
+			want: map[uint16]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProvisionalBonus(tt.bps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProvisionalBonus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvisionalBonusKeepsInputOrder(t *testing.T) {
+	bps := elVals(1, 10, 2, 30, 3, 20)
+	want := append([]fpl.ElVal(nil), bps...)
+	ProvisionalBonus(bps)
+	if !reflect.DeepEqual(bps, want) {
+		t.Errorf("input reordered to %v", bps)
+	}
+}
+
+// TestFixtureBonus checks every fixture of the captured gameweek: the
+// feed's confirmed bonus once finished, and the same bonus derived from
+// BPS while the fixture is only provisionally finished.
+func TestFixtureBonus(t *testing.T) {
+	var fixtures fpl.Fixtures
+	readJSON(t, "data-fixtures/data-fixtures.json", &fixtures)
+
+	want := map[int]map[uint16]int{
+		141: {49: 3, 48: 2, 34: 1},
+		142: {620: 3, 134: 2, 105: 1},
+		143: {83: 3, 76: 2, 77: 1},
+		144: {259: 3, 586: 2, 249: 1},
+		145: {267: 3, 558: 3, 256: 1}, // tied for first
+		146: {8: 3, 540: 2, 321: 1},
+		147: {290: 3, 313: 2, 309: 1},
+		148: {511: 3, 506: 2, 663: 2}, // tied for second
+		149: {573: 3, 551: 2, 557: 2}, // tied for second
+		150: {145: 3, 392: 2, 362: 1},
+	}
+	if len(fixtures) != len(want) {
+		t.Fatalf("capture has %d fixtures, want %d", len(fixtures), len(want))
+	}
+	for _, f := range fixtures {
+		bonus, state := FixtureBonus(f)
+		if state != BonusConfirmed || BonusStateOf(f) != BonusConfirmed {
+			t.Errorf("fixture %d: state %s, want %s", f.ID, state, BonusConfirmed)
+		}
+		if !reflect.DeepEqual(bonus, want[f.ID]) {
+			t.Errorf("fixture %d: bonus %v, want %v", f.ID, bonus, want[f.ID])
+		}
+
+		f.Finished = false
+		bonus, state = FixtureBonus(f)
+		if state != BonusProvisional || BonusStateOf(f) != BonusProvisional {
+			t.Errorf("fixture %d unconfirmed: state %s, want %s", f.ID, state, BonusProvisional)
+		}
+		if !reflect.DeepEqual(bonus, want[f.ID]) {
+			t.Errorf("fixture %d unconfirmed: bonus %v, want %v", f.ID, bonus, want[f.ID])
+		}
+
+		f.Started, f.FinishedProvisional = false, false
+		if bonus, state = FixtureBonus(f); state != BonusNone || len(bonus) != 0 {
+			t.Errorf("fixture %d not started: %v, %s", f.ID, bonus, state)
+		}
+	}
+}
+
+func TestReconcileBonus(t *testing.T) {
+	tests := []struct {
+		name         string
+		stats        fpl.Stats
+		tally        BonusTally
+		points, want int
+	}{
+		{
+			name:   "live feed already includes confirmed bonus",
+			stats:  fpl.Stats{TotalPoints: 10, Bonus: 3},
+			tally:  BonusTally{Confirmed: 3},
+			points: 10, want: 3,
+		},
+		{
+			name:   "fixtures confirm bonus before the live feed",
+			stats:  fpl.Stats{TotalPoints: 7},
+			tally:  BonusTally{Confirmed: 3},
+			points: 10, want: 3,
+		},
+		{
+			name:   "provisional bonus while the fixture is played",
+			stats:  fpl.Stats{TotalPoints: 6},
+			tally:  BonusTally{Provisional: 2},
+			points: 8, want: 2,
+		},
+		{
+			name:   "double gameweek, one fixture confirmed and one provisional",
+			stats:  fpl.Stats{TotalPoints: 12, Bonus: 2},
+			tally:  BonusTally{Confirmed: 2, Provisional: 1},
+			points: 13, want: 3,
+		},
+		{
+			name:   "double gameweek, live feed behind on the confirmed fixture",
+			stats:  fpl.Stats{TotalPoints: 10},
+			tally:  BonusTally{Confirmed: 2, Provisional: 1},
+			points: 13, want: 3,
+		},
+		{
+			name:   "double gameweek, live feed ahead of the fixtures",
+			stats:  fpl.Stats{TotalPoints: 13, Bonus: 3},
+			tally:  BonusTally{Confirmed: 2, Provisional: 1},
+			points: 13, want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, bonus := ReconcileBonus(tt.stats, tt.tally)
+			if points != tt.points || bonus != tt.want {
+				t.Errorf("ReconcileBonus() = %d, %d; want %d, %d", points, bonus, tt.points, tt.want)
+			}
+		})
+	}
+}
+
+// TestReconcileBonusCaptured runs players from the captured live feed
+// against the captured fixtures, which were taken at different moments:
+// the fixtures confirm bonus the live feed lacks for some players, and
+// the live feed holds bonus from fixtures outside the capture for others.
+func TestReconcileBonusCaptured(t *testing.T) {
+	var live fpl.Live
+	readJSON(t, "data-live.json", &live)
+	var fixtures fpl.Fixtures
+	readJSON(t, "data-fixtures/data-fixtures.json", &fixtures)
+
+	tallies := map[uint16]BonusTally{}
+	for _, f := range fixtures {
+		bonus, state := FixtureBonus(f)
+		for el, b := range bonus {
+			tally := tallies[el]
+			if state == BonusConfirmed {
+				tally.Confirmed += b
+			} else {
+				tally.Provisional += b
+			}
+			tallies[el] = tally
+		}
+	}
+
+	tests := []struct {
+		element       uint16
+		points, bonus int
+	}{
+		{element: 267, points: 7, bonus: 3}, // live 4 without bonus, fixture confirms 3
+		{element: 19, points: 10, bonus: 3}, // live 10 including 3 bonus
+		{element: 558, points: 4, bonus: 3}, // live 1 without bonus, tied for first
+		{element: 49, points: 4, bonus: 3},  // live 1 without bonus
+		{element: 620, points: 3, bonus: 3}, // bonus confirmed, no live points yet
+		{element: 13, points: 8, bonus: 2},  // live 8 including 2 bonus
+		{element: 1, points: 0, bonus: 0},   // no bonus anywhere
+	}
+	for _, tt := range tests {
+		points, bonus := ReconcileBonus(live.El[tt.element].Stats, tallies[tt.element])
+		if points != tt.points || bonus != tt.bonus {
+			t.Errorf("element %d: ReconcileBonus() = %d, %d; want %d, %d", tt.element, points, bonus, tt.points, tt.bonus)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package scoring applies a league's rules to live stats: points per
+// player, draft auto-substitutions and bonus points, confirmed or derived
+// from BPS while fixtures are in progress.
+package scoring
+
+import "draft.kparajuli.com/m/fpl"
+
+// Element type IDs as used by bootstrap-static element_types.
+const (
+	ElementGKP = 1
+	ElementDEF = 2
+	ElementMID = 3
+	ElementFWD = 4
+)
+
+// PointsLine is one stat's contribution to a player's points.
+type PointsLine struct {
+	Stat   string `json:"stat"`
+	Value  int    `json:"value"`
+	Points int    `json:"points"`
+}
+
+// PointsBreakdown is a player's points recomputed from raw stats.
+// Mismatch is set when Total disagrees with the live feed's total_points.
+type PointsBreakdown struct {
+	Lines    []PointsLine `json:"lines"`
+	Total    int          `json:"total"`
+	APITotal int          `json:"api_total"`
+	Mismatch bool         `json:"mismatch"`
+}
+
+// byPosition returns the position dependent rules for an element type:
+// points per goal, per clean sheet and per concede_limit goals conceded.
+func byPosition(r fpl.ScoringSettings, elementType int) (goal, cleanSheet, conceded int) {
+	switch elementType {
+	case ElementGKP:
+		return r.GoalsScoredGKP, r.CleanSheetsGKP, r.GoalsConcededGKP
+	case ElementDEF:
+		return r.GoalsScoredDEF, r.CleanSheetsDEF, r.GoalsConcededDEF
+	case ElementMID:
+		return r.GoalsScoredMID, r.CleanSheetsMID, r.GoalsConcededMID
+	case ElementFWD:
+		return r.GoalsScoredFWD, r.CleanSheetsFWD, r.GoalsConcededFWD
+	}
+	return 0, 0, 0
+}
+
+// Score applies the league's scoring rules to one player's live
+// stats. Bonus is counted as the feed reports it, so the total is
+// comparable with fpl.Stats.TotalPoints.
+func Score(rules fpl.ScoringSettings, elementType int, s fpl.Stats) PointsBreakdown {
+	goal, cleanSheet, conceded := byPosition(rules, elementType)
+
+	minutes := 0
+	if s.Minutes >= rules.LongPlayLimit && rules.LongPlayLimit > 0 {
+		minutes = rules.LongPlay
+	} else if s.Minutes > 0 {
+		minutes = rules.ShortPlay
+	}
+
+	concededPts, savesPts := 0, 0
+	if rules.ConcedeLimit > 0 {
+		concededPts = s.GoalsConceded / rules.ConcedeLimit * conceded
+	}
+	if rules.SavesLimit > 0 {
+		savesPts = s.Saves / rules.SavesLimit * rules.Saves
+	}
+
+	lines := []PointsLine{
+		{"minutes", s.Minutes, minutes},
+		{"goals_scored", s.GoalsScored, s.GoalsScored * goal},
+		{"assists", s.Assists, s.Assists * rules.Assists},
+		{"clean_sheets", s.CleanSheets, s.CleanSheets * cleanSheet},
+		{"goals_conceded", s.GoalsConceded, concededPts},
+		{"saves", s.Saves, savesPts},
+		{"penalties_saved", s.PenaltiesSaved, s.PenaltiesSaved * rules.PenaltiesSaved},
+		{"penalties_missed", s.PenaltiesMissed, s.PenaltiesMissed * rules.PenaltiesMissed},
+		{"yellow_cards", s.YellowCards, s.YellowCards * rules.YellowCards},
+		{"red_cards", s.RedCards, s.RedCards * rules.RedCards},
+		{"own_goals", s.OwnGoals, s.OwnGoals * rules.OwnGoals},
+		{"bonus", s.Bonus, s.Bonus * rules.Bonus},
+	}
+
+	b := PointsBreakdown{APITotal: s.TotalPoints}
+	for _, line := range lines {
+		if line.Points == 0 {
+			continue
+		}
+		b.Lines = append(b.Lines, line)
+		b.Total += line.Points
+	}
+	b.Mismatch = b.Total != b.APITotal
+	return b
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"draft.kparajuli.com/m/dashboard"
+)
+
+type StandingsResponse struct {
+	LeagueID      int                     `json:"league_id"`
+	Gameweek      int                     `json:"gameweek"`
+	Standings     []dashboard.StandingRow `json:"standings"`
+	LiveStandings []dashboard.StandingRow `json:"live_standings"`
+}
+
+type MatchupsResponse struct {
+	LeagueID int                 `json:"league_id"`
+	Gameweek int                 `json:"gameweek"`
+	Matchups []dashboard.Matchup `json:"matchups"`
+}
+
+// FixturesResponse has this gameweek's Premier League fixtures and the
+// league's head-to-head pairings for the next one.
+type FixturesResponse struct {
+	LeagueID     int                       `json:"league_id"`
+	Gameweek     int                       `json:"gameweek"`
+	Fixtures     []dashboard.FixtureResult `json:"fixtures"`
+	NextGameweek int                       `json:"next_gameweek"`
+	NextPairings []dashboard.Pairing       `json:"next_pairings"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// apiHandler serves the computed dashboard as JSON under /api/. The
+// league defaults to the first configured one and is picked with
+// ?league=ID.
+func (s *Server) apiHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		leagueID := s.Leagues[0]
+		if v := r.URL.Query().Get("league"); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid league id")
+				return
+			}
+			leagueID = id
+		}
+
+		p, ok := s.Pollers[leagueID]
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown league")
+			return
+		}
+		snap, ready := p.Snapshot()
+		if !ready {
+			writeJSONError(w, http.StatusServiceUnavailable, "dashboard is still loading")
+			return
+		}
+		dash := snap.Dashboard
+
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case path == "/api/standings":
+			writeJSON(w, http.StatusOK, StandingsResponse{
+				LeagueID:      dash.LeagueID,
+				Gameweek:      dash.Gameweek,
+				Standings:     dash.Standings,
+				LiveStandings: dash.LiveStandings,
+			})
+		case path == "/api/matchups":
+			writeJSON(w, http.StatusOK, MatchupsResponse{
+				LeagueID: dash.LeagueID,
+				Gameweek: dash.Gameweek,
+				Matchups: dash.Matchups,
+			})
+		case path == "/api/fixtures":
+			writeJSON(w, http.StatusOK, FixturesResponse{
+				LeagueID:     dash.LeagueID,
+				Gameweek:     dash.Gameweek,
+				Fixtures:     dash.Fixtures,
+				NextGameweek: dash.Gameweek + 1,
+				NextPairings: dash.NextFixtures,
+			})
+		case path == "/api/transactions":
+			history, err := dashboard.BuildTransactions(r.Context(), s.Source, leagueID)
+			if err != nil {
+				writeJSONError(w, http.StatusBadGateway, "could not load league data")
+				return
+			}
+			writeJSON(w, http.StatusOK, history)
+		case path == "/api/trades":
+			trades, err := dashboard.BuildTrades(r.Context(), s.Source, leagueID)
+			if err != nil {
+				writeJSONError(w, http.StatusBadGateway, "could not load league data")
+				return
+			}
+			writeJSON(w, http.StatusOK, trades)
+		case path == "/api/players":
+			filter, err := parsePlayerFilter(r.URL.Query())
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			players, err := dashboard.BuildPlayers(r.Context(), s.Source, leagueID, filter)
+			if err != nil {
+				writeJSONError(w, http.StatusBadGateway, "could not load league data")
+				return
+			}
+			writeJSON(w, http.StatusOK, players)
+		case strings.HasPrefix(path, "/api/gameweek/"):
+			gw, err := strconv.Atoi(strings.TrimPrefix(path, "/api/gameweek/"))
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid gameweek")
+				return
+			}
+			dash, err := p.Gameweek(r.Context(), gw)
+			if errors.Is(err, dashboard.ErrGameweekOutOfRange) {
+				writeJSONError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusBadGateway, "could not load league data")
+				return
+			}
+			writeJSON(w, http.StatusOK, dash)
+		default:
+			writeJSONError(w, http.StatusNotFound, "not found")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"draft.kparajuli.com/m/fpl"
+)
+
+// leaguePage serves one of a league's season pages, such as
+// "/transactions" or "/league/{id}/transactions"; base is the path
+// without the page's suffix. build loads the view that the template name
+// renders.
+func (s *Server) leaguePage(w http.ResponseWriter, r *http.Request, base, name string, build func(ctx context.Context, src fpl.Source, leagueID int) (interface{}, error)) {
+	leagueID, gw, ok := parseDashboardPath(base)
+	if !ok || gw != 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if leagueID == 0 {
+		leagueID = s.Leagues[0]
+	}
+	if _, ok := s.Pollers[leagueID]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := strings.TrimSuffix(name, "_page")
+	view, err := build(r.Context(), s.Source, leagueID)
+	if err != nil {
+		log.Printf("league %d %s: %v", leagueID, page, err)
+		http.Error(w, "could not load league data", http.StatusBadGateway)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := s.Views.Render(&buf, name, view); err != nil {
+		log.Printf("league %d: render %s: %v", leagueID, page, err)
+		http.Error(w, "could not render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
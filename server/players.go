@@ -0,0 +1,42 @@
+package server
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"draft.kparajuli.com/m/dashboard"
+)
+
+// parsePlayerFilter reads the player filter from ?position=, ?team=,
+// ?status=, ?form= (minimum), ?points= (minimum total) and ?ownership=
+// ("owned" or "free").
+func parsePlayerFilter(q url.Values) (dashboard.PlayerFilter, error) {
+	f := dashboard.PlayerFilter{
+		Position:  strings.ToUpper(q.Get("position")),
+		Team:      strings.ToUpper(q.Get("team")),
+		Status:    strings.ToLower(q.Get("status")),
+		Ownership: strings.ToLower(q.Get("ownership")),
+	}
+	if v := q.Get("form"); v != "" {
+		form, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return f, errors.New("invalid form")
+		}
+		f.MinForm = form
+	}
+	if v := q.Get("points"); v != "" {
+		points, err := strconv.Atoi(v)
+		if err != nil {
+			return f, errors.New("invalid points")
+		}
+		f.MinPoints = points
+	}
+	switch f.Ownership {
+	case "", "owned", "free":
+	default:
+		return f, errors.New("invalid ownership")
+	}
+	return f, nil
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"draft.kparajuli.com/m/dashboard"
+	"draft.kparajuli.com/m/history"
+)
+
+// replayHandler serves "/replay/gw/{n}" and "/replay/league/{id}/gw/{n}":
+// the dashboard of a recorded gameweek as it looked at snapshot ?i=
+// (default: the last one). Standings are today's, so the live table is
+// left out.
+func (s *Server) replayHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		leagueID, gw, ok := parseDashboardPath(strings.TrimPrefix(r.URL.Path, "/replay"))
+		if !ok || gw == 0 || gw > 255 {
+			http.NotFound(w, r)
+			return
+		}
+		if leagueID == 0 {
+			leagueID = s.Leagues[0]
+		}
+		known := false
+		for _, id := range s.Leagues {
+			known = known || id == leagueID
+		}
+		if !known {
+			http.NotFound(w, r)
+			return
+		}
+
+		times, err := s.Recording.Times(uint8(gw))
+		if err != nil || len(times) == 0 {
+			http.Error(w, "no recording for this gameweek", http.StatusNotFound)
+			return
+		}
+		view := dashboard.ReplayView{Times: times, Index: len(times) - 1}
+		if v := r.URL.Query().Get("i"); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil || i < 0 || i >= len(times) {
+				http.Error(w, "invalid snapshot index", http.StatusBadRequest)
+				return
+			}
+			view.Index = i
+		}
+
+		replay := history.NewReplay(s.Source, *s.Recording, uint8(gw), view.At())
+		dash, err := dashboard.Build(r.Context(), replay, leagueID, uint8(gw))
+		if err != nil {
+			log.Printf("replay league %d gw %d: %v", leagueID, gw, err)
+			http.Error(w, "could not load league data", http.StatusBadGateway)
+			return
+		}
+		dash.LiveStandings = nil
+		dash.Replay = &view
+
+		var buf bytes.Buffer
+		if err := s.Views.Render(&buf, "layout", dash); err != nil {
+			log.Printf("replay league %d: render: %v", leagueID, err)
+			http.Error(w, "could not render page", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		buf.WriteTo(w)
+	}
+}
@@ -0,0 +1,170 @@
+// Package server serves the dashboard pages, the JSON API and the replay
+// and debug endpoints over HTTP.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"draft.kparajuli.com/m/dashboard"
+	"draft.kparajuli.com/m/fpl"
+	"draft.kparajuli.com/m/history"
+	"draft.kparajuli.com/m/render"
+)
+
+// Server serves the leagues kept up to date by Pollers.
+type Server struct {
+	// Leagues are the league IDs served; the first is the default.
+	Leagues []int
+	Pollers map[int]*dashboard.Poller
+	Views   *render.Renderer
+	// Source builds replayed gameweeks and Cache backs /debug/cache.
+	Source fpl.Source
+	Cache  *fpl.CachedSource
+	// Recording enables /replay/ when set.
+	Recording *history.Recording
+}
+
+// Handler routes every endpoint of the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.leagueHandler())
+	mux.HandleFunc("/api/", s.apiHandler())
+	if s.Recording != nil {
+		mux.HandleFunc("/replay/", s.replayHandler())
+	}
+	if s.Cache != nil {
+		mux.HandleFunc("/debug/cache", s.cacheStatsHandler())
+	}
+	return mux
+}
+
+func (s *Server) cacheStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.Cache.Stats())
+	}
+}
+
+// parseDashboardPath splits "/", "/gw/{n}", "/league/{id}" and
+// "/league/{id}/gw/{n}" into a league and gameweek, 0 meaning default.
+func parseDashboardPath(path string) (leagueID, gw int, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) == 1 && parts[0] == "" {
+		return 0, 0, true
+	}
+	if parts[0] == "league" {
+		if len(parts) < 2 {
+			return 0, 0, false
+		}
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, 0, false
+		}
+		leagueID = id
+		parts = parts[2:]
+	}
+	if len(parts) == 0 {
+		return leagueID, 0, true
+	}
+	if len(parts) != 2 || parts[0] != "gw" {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil || n <= 0 {
+		return 0, 0, false
+	}
+	return leagueID, n, true
+}
+
+// leagueHandler serves the default league on "/" and every configured
+// league on "/league/{id}". The current gameweek comes from the poller's
+// last snapshot; any other gameweek of the season can be picked with
+// "/gw/{n}" or "?gw=n". Paths ending in "/transactions" or "/trades"
+// show the season's transactions or trades instead, and "/players" the
+// league's player pool.
+func (s *Server) leagueHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if base, ok := strings.CutSuffix(r.URL.Path, "/transactions"); ok {
+			s.leaguePage(w, r, base, "transactions_page", func(ctx context.Context, src fpl.Source, id int) (interface{}, error) {
+				return dashboard.BuildTransactions(ctx, src, id)
+			})
+			return
+		}
+		if base, ok := strings.CutSuffix(r.URL.Path, "/trades"); ok {
+			s.leaguePage(w, r, base, "trades_page", func(ctx context.Context, src fpl.Source, id int) (interface{}, error) {
+				return dashboard.BuildTrades(ctx, src, id)
+			})
+			return
+		}
+		if base, ok := strings.CutSuffix(r.URL.Path, "/players"); ok {
+			filter, err := parsePlayerFilter(r.URL.Query())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.leaguePage(w, r, base, "players_page", func(ctx context.Context, src fpl.Source, id int) (interface{}, error) {
+				return dashboard.BuildPlayers(ctx, src, id, filter)
+			})
+			return
+		}
+
+		leagueID, gw, ok := parseDashboardPath(r.URL.Path)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if leagueID == 0 {
+			leagueID = s.Leagues[0]
+		}
+		if v := r.URL.Query().Get("gw"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "invalid gameweek", http.StatusBadRequest)
+				return
+			}
+			gw = n
+		}
+
+		p, ok := s.Pollers[leagueID]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if _, ready := p.Snapshot(); !ready && gw == 0 {
+			if err := p.Err(); err != nil {
+				http.Error(w, "could not load league data", http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "dashboard is still loading, try again shortly", http.StatusServiceUnavailable)
+			return
+		}
+
+		dash, err := p.Gameweek(r.Context(), gw)
+		if errors.Is(err, dashboard.ErrGameweekOutOfRange) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("league %d gw %d: %v", leagueID, gw, err)
+			http.Error(w, "could not load league data", http.StatusBadGateway)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := s.Views.Render(&buf, "layout", dash); err != nil {
+			log.Printf("league %d: render: %v", leagueID, err)
+			http.Error(w, "could not render page", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		buf.WriteTo(w)
+	}
+}
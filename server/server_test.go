@@ -0,0 +1,55 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"draft.kparajuli.com/m/dashboard"
+	"draft.kparajuli.com/m/fpl/fpltest"
+	"draft.kparajuli.com/m/render"
+	"draft.kparajuli.com/m/server"
+)
+
+const testLeague = 29143
+
+func TestLeaguePages(t *testing.T) {
+	src := fpltest.NewSource(t, "../fpl/testdata")
+	views, err := render.New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server.Server{
+		Leagues: []int{testLeague},
+		Pollers: map[int]*dashboard.Poller{testLeague: dashboard.NewPoller(src, testLeague)},
+		Views:   views,
+		Source:  src,
+	}
+	handler := s.Handler()
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/transactions", http.StatusOK, "TRANSACTIONS"},
+		{"/league/29143/transactions", http.StatusOK, "TRANSACTIONS"},
+		{"/league/29143/trades", http.StatusOK, "J.Ayew"},
+		{"/players?ownership=free&position=FWD", http.StatusOK, "Free agent"},
+		{"/league/29143/players?team=ARS", http.StatusOK, "Ødegaard"},
+		{"/league/1/trades", http.StatusNotFound, ""},
+		{"/gw/3/transactions", http.StatusNotFound, ""},
+		{"/players?points=lots", http.StatusBadRequest, "invalid points"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.status)
+		}
+		if !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("GET %s: body lacks %q", tt.path, tt.body)
+		}
+	}
+}